@@ -0,0 +1,198 @@
+package collections
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to a DelayQueue.
+// It allows tests to control time instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	// when the timer fires.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing.
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// DelayHandle is returned by DelayQueue.Schedule and identifies a
+// scheduled element for Cancel and Reschedule.
+type DelayHandle[T any] struct {
+	item T
+	at   time.Time
+}
+
+// Item returns the scheduled element.
+func (h *DelayHandle[T]) Item() T {
+	return h.item
+}
+
+// DelayQueue holds elements until their deadline has passed.
+// Take returns the elements in order of their deadlines, elements
+// with the same deadline in the order they were scheduled.
+//
+// It is built on an indexed MaxPQ where earlier deadlines have
+// higher priority, so Cancel and Reschedule run in O(log n).
+//
+// All operations of this type are thread-safe.
+type DelayQueue[T any] struct {
+	mu    sync.Mutex
+	pq    *MaxPQ[*DelayHandle[T]]
+	clock Clock
+	// wake is closed and replaced whenever the earliest deadline may
+	// have changed, to wake up the goroutines blocked in Take.
+	wake chan struct{}
+}
+
+// Schedule adds the given element to the queue, to become available
+// at the given time. The returned handle can be used to cancel or
+// reschedule the element.
+func (q *DelayQueue[T]) Schedule(item T, at time.Time) *DelayHandle[T] {
+	h := &DelayHandle[T]{item: item, at: at}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pq.Insert(h)
+	q.notify()
+	return h
+}
+
+// Take blocks until the element with the earliest deadline becomes
+// available, then deletes and returns it. It returns ctx.Err() if
+// the context is done before any element becomes available.
+func (q *DelayQueue[T]) Take(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		item, wait, ok := q.poll()
+		wake := q.wake
+		q.mu.Unlock()
+		if ok {
+			return item, nil
+		}
+
+		var timer Timer
+		var fired <-chan time.Time
+		if wait > 0 {
+			timer = q.clock.NewTimer(wait)
+			fired = timer.C()
+		}
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			var zero T
+			return zero, ctx.Err()
+		case <-wake:
+		case <-fired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// TryTake deletes and returns the element with the earliest deadline
+// if that deadline has passed, without blocking.
+func (q *DelayQueue[T]) TryTake() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item, _, ok := q.poll()
+	return item, ok
+}
+
+// Cancel removes the element identified by the given handle from the
+// queue. It returns false if the element was already taken or cancelled.
+func (q *DelayQueue[T]) Cancel(h *DelayHandle[T]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.pq.Remove(h) {
+		return false
+	}
+	q.notify()
+	return true
+}
+
+// Reschedule changes the deadline of the element identified by the given
+// handle. It returns false if the element was already taken or cancelled.
+func (q *DelayQueue[T]) Reschedule(h *DelayHandle[T], at time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	i := q.pq.find(h)
+	if i < 0 {
+		return false
+	}
+	h.at = at
+	q.pq.fix(i)
+	q.notify()
+	return true
+}
+
+// Size returns the number of scheduled elements, including the ones
+// whose deadline has already passed.
+func (q *DelayQueue[T]) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pq.Size()
+}
+
+// poll deletes and returns the earliest element if its deadline has
+// passed, otherwise it returns the time to wait for it. A zero wait
+// means the queue is empty.
+func (q *DelayQueue[T]) poll() (T, time.Duration, bool) {
+	var zero T
+	if q.pq.Size() == 0 {
+		return zero, 0, false
+	}
+	h := q.pq.PeekMax()
+	if wait := h.at.Sub(q.clock.Now()); wait > 0 {
+		return zero, wait, false
+	}
+	q.pq.DelMax()
+	return h.item, 0, true
+}
+
+func (q *DelayQueue[T]) notify() {
+	close(q.wake)
+	q.wake = make(chan struct{})
+}
+
+// NewDelayQueue creates a new empty DelayQueue using the given clock.
+// A nil clock uses the system time.
+func NewDelayQueue[T any](clock Clock) *DelayQueue[T] {
+	if clock == nil {
+		clock = realClock{}
+	}
+	pq := NewMaxPQWithOptions(0, func(o1, o2 *DelayHandle[T]) int {
+		return o2.at.Compare(o1.at)
+	}, MaxPQOptions{TieBreak: FIFO, Indexed: true})
+	return &DelayQueue[T]{
+		pq:    pq,
+		clock: clock,
+		wake:  make(chan struct{}),
+	}
+}
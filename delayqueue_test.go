@@ -0,0 +1,182 @@
+package collections
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	// created receives a value every time a timer is created
+	created chan struct{}
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		created: make(chan struct{}, 100),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	c.created <- struct{}{}
+	return t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, other := range t.clock.timers {
+		if other == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func TestDelayQueue_TryTake(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[string](clock)
+	q.Schedule("a", clock.Now().Add(2*time.Second))
+	q.Schedule("b", clock.Now().Add(time.Second))
+	q.Schedule("c", clock.Now().Add(time.Second))
+
+	_, ok := q.TryTake()
+	require.False(t, ok)
+
+	clock.Advance(time.Second)
+	for _, want := range []string{"b", "c"} {
+		got, ok := q.TryTake()
+		require.True(t, ok)
+		require.EqualValues(t, want, got)
+	}
+	_, ok = q.TryTake()
+	require.False(t, ok)
+
+	clock.Advance(time.Second)
+	got, ok := q.TryTake()
+	require.True(t, ok)
+	require.EqualValues(t, "a", got)
+	require.EqualValues(t, 0, q.Size())
+}
+
+func TestDelayQueue_Take(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[int](clock)
+	q.Schedule(1, clock.Now().Add(time.Minute))
+
+	got := make(chan int)
+	go func() {
+		item, err := q.Take(context.Background())
+		if err != nil {
+			t.Errorf("Take() error = %v", err)
+		}
+		got <- item
+	}()
+
+	<-clock.created
+	clock.Advance(time.Minute)
+	require.EqualValues(t, 1, <-got)
+}
+
+func TestDelayQueue_TakeWokenBySchedule(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[int](clock)
+	q.Schedule(1, clock.Now().Add(time.Hour))
+
+	got := make(chan int)
+	go func() {
+		item, _ := q.Take(context.Background())
+		got <- item
+	}()
+
+	<-clock.created
+	q.Schedule(2, clock.Now())
+	require.EqualValues(t, 2, <-got)
+	require.EqualValues(t, 1, q.Size())
+}
+
+func TestDelayQueue_TakeCancelled(t *testing.T) {
+	q := NewDelayQueue[int](newFakeClock())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := q.Take(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDelayQueue_CancelReschedule(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue[string](clock)
+	a := q.Schedule("a", clock.Now().Add(time.Second))
+	b := q.Schedule("b", clock.Now().Add(2*time.Second))
+	c := q.Schedule("c", clock.Now().Add(3*time.Second))
+
+	require.True(t, q.Cancel(a))
+	require.False(t, q.Cancel(a))
+	require.True(t, q.Reschedule(c, clock.Now()))
+
+	got, ok := q.TryTake()
+	require.True(t, ok)
+	require.EqualValues(t, "c", got)
+	require.False(t, q.Reschedule(c, clock.Now()))
+
+	require.True(t, q.Reschedule(b, clock.Now().Add(time.Hour)))
+	clock.Advance(time.Minute)
+	_, ok = q.TryTake()
+	require.False(t, ok)
+	require.EqualValues(t, "b", b.Item())
+}
+
+func TestDelayQueue_RealClock(t *testing.T) {
+	q := NewDelayQueue[int](nil)
+	q.Schedule(1, time.Now().Add(10*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got, err := q.Take(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, got)
+}
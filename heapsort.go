@@ -0,0 +1,56 @@
+package collections
+
+// HeapSort sorts the given slice in ascending order in place, in
+// O(n log n) time, using the same heap operations as MaxPQ.
+// A nil compareFn compares primitive values the same way MaxPQ does.
+func HeapSort[T comparable](s []T, compareFn Comparator[T]) {
+	sortHeap(Heapify(s, compareFn))
+}
+
+// PartialSort rearranges the given slice in place so that its first k
+// elements are the k smallest ones in ascending order. The order of
+// the remaining elements is unspecified. It runs in O(n log k) time.
+func PartialSort[T comparable](s []T, k int, compareFn Comparator[T]) {
+	if k <= 0 {
+		return
+	}
+	if k > len(s) {
+		k = len(s)
+	}
+	sortHeap(selectSmallest(s, k, compareFn))
+}
+
+// NthElement rearranges the given slice in place so that the element
+// at index k is the one that would be there if the slice was sorted,
+// no element before it is greater and no element after it is smaller.
+// It runs in O(n log k) time and does nothing if k is out of range.
+func NthElement[T comparable](s []T, k int, compareFn Comparator[T]) {
+	if k < 0 || k >= len(s) {
+		return
+	}
+	selectSmallest(s, k+1, compareFn)
+	s[0], s[k] = s[k], s[0]
+}
+
+// selectSmallest moves the k smallest elements of s to its first k
+// positions, arranged as a max heap which is returned.
+func selectSmallest[T comparable](s []T, k int, compareFn Comparator[T]) *MaxPQ[T] {
+	pq := Heapify(s[:k], compareFn)
+	for i := k; i < len(s); i++ {
+		if pq.compare(s[i], s[0]) < 0 {
+			s[0], s[i] = s[i], s[0]
+			pq.sink(0)
+		}
+	}
+	return pq
+}
+
+// sortHeap sorts the elements of the given heap in ascending order
+// in place by repeatedly moving the max element to the end.
+func sortHeap[T comparable](pq *MaxPQ[T]) {
+	for n := len(pq.items) - 1; n > 0; n-- {
+		pq.exch(0, n)
+		pq.items = pq.items[:n]
+		pq.sink(0)
+	}
+}
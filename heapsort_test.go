@@ -0,0 +1,114 @@
+package collections
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomInts(r *rand.Rand, n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = r.Intn(n + 1)
+	}
+	return s
+}
+
+func TestHeapSort(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for n := 0; n < 100; n++ {
+		got := randomInts(r, n)
+		want := slices.Clone(got)
+		slices.Sort(want)
+
+		HeapSort(got, nil)
+		require.EqualValues(t, want, got)
+	}
+}
+
+func TestHeapSort_Comparator(t *testing.T) {
+	s := []temp{{val: 3}, {val: 1}, {val: 2}}
+	HeapSort(s, Compare)
+
+	require.EqualValues(t, []temp{{val: 1}, {val: 2}, {val: 3}}, s)
+}
+
+func TestPartialSort(t *testing.T) {
+	r := rand.New(rand.NewSource(10))
+	for n := 0; n < 50; n++ {
+		for k := -1; k <= n+1; k++ {
+			got := randomInts(r, n)
+			want := slices.Clone(got)
+			slices.Sort(want)
+
+			PartialSort(got, k, nil)
+			m := k
+			if m < 0 {
+				m = 0
+			} else if m > n {
+				m = n
+			}
+			require.EqualValues(t, want[:m], got[:m])
+			rest := got[m:]
+			slices.Sort(rest)
+			require.EqualValues(t, want[m:], rest)
+		}
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for n := 1; n < 50; n++ {
+		for k := 0; k < n; k++ {
+			got := randomInts(r, n)
+			want := slices.Clone(got)
+			slices.Sort(want)
+
+			NthElement(got, k, nil)
+			require.EqualValues(t, want[k], got[k])
+			for i := range got {
+				if i < k {
+					require.LessOrEqual(t, got[i], got[k])
+				} else {
+					require.GreaterOrEqual(t, got[i], got[k])
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkPartialSort(b *testing.B) {
+	const n = 100000
+	r := rand.New(rand.NewSource(1))
+	data := randomInts(r, n)
+	s := make([]int, n)
+	for _, k := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("PartialSort/k_%d", k), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(s, data)
+				PartialSort(s, k, nil)
+			}
+		})
+		b.Run(fmt.Sprintf("NthElement/k_%d", k), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copy(s, data)
+				NthElement(s, k, nil)
+			}
+		})
+	}
+	b.Run("HeapSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			copy(s, data)
+			HeapSort(s, nil)
+		}
+	})
+	b.Run("slices.Sort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			copy(s, data)
+			slices.Sort(s)
+		}
+	})
+}
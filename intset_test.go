@@ -0,0 +1,147 @@
+package collections
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntSet_AddRemove(t *testing.T) {
+	s := NewIntSet(10)
+	for _, e := range []int{5, 0, 63, 64, 1000, 5} {
+		s.Add(e)
+	}
+
+	require.EqualValues(t, 5, s.Size())
+	require.EqualValues(t, []int{0, 5, 63, 64, 1000}, s.Items())
+	require.True(t, s.Contains(63))
+	require.False(t, s.Contains(62))
+	require.False(t, s.Contains(-1))
+	require.False(t, s.Contains(5000))
+
+	require.True(t, s.Remove(64))
+	require.False(t, s.Remove(64))
+	require.False(t, s.Remove(5000))
+	require.EqualValues(t, []int{0, 5, 63, 1000}, s.Items())
+
+	s.Clear()
+	require.EqualValues(t, 0, s.Size())
+	s.Add(3)
+	require.EqualValues(t, []int{3}, s.Items())
+}
+
+func TestIntSet_AddNegative(t *testing.T) {
+	var s IntSet
+	require.Panics(t, func() { s.Add(-1) })
+}
+
+func TestIntSet_ZeroValue(t *testing.T) {
+	var s IntSet
+	s.Add(100)
+	require.True(t, s.Contains(100))
+
+	var nilSet *IntSet
+	require.EqualValues(t, 0, nilSet.Size())
+	require.False(t, nilSet.Contains(1))
+	require.Empty(t, nilSet.Items())
+	require.True(t, nilSet.IsSubsetOf(&s))
+	require.True(t, nilSet.Equal(NewIntSet(0)))
+	require.EqualValues(t, []int{100}, nilSet.Union(&s).Items())
+}
+
+func TestIntSet_Operations(t *testing.T) {
+	sets := map[string][]int{
+		"empty":    {},
+		"a":        {1, 2, 3, 64, 200},
+		"overlap":  {3, 64, 65, 300},
+		"subset":   {2, 64},
+		"disjoint": {4, 128},
+		"random":   rand.Perm(500)[:100],
+	}
+	for n1, a1 := range sets {
+		for n2, a2 := range sets {
+			t.Run(n1+"_"+n2, func(t *testing.T) {
+				s1, s2 := IntSetFromArray(a1), IntSetFromArray(a2)
+				r1, r2 := FromArray(a1), FromArray(a2)
+				sorted := func(s *Set[int]) []int {
+					items := s.Items()
+					slices.Sort(items)
+					return items
+				}
+
+				require.EqualValues(t, sorted(r1.Union(r2)), s1.Union(s2).Items())
+				require.EqualValues(t, sorted(r1.Intersection(r2)), s1.Intersection(s2).Items())
+				require.EqualValues(t, sorted(r1.Difference(r2)), s1.Difference(s2).Items())
+				require.EqualValues(t, sorted(r1.SymmetricDifference(r2)), s1.SymmetricDifference(s2).Items())
+				require.EqualValues(t, r1.IsSubsetOf(r2), s1.IsSubsetOf(s2))
+				require.EqualValues(t, r1.IsDisjoint(r2), s1.IsDisjoint(s2))
+				require.EqualValues(t, r1.Equal(r2), s1.Equal(s2))
+
+				c := s1.Clone()
+				c.UnionWith(s2)
+				require.True(t, c.Equal(s1.Union(s2)))
+				c = s1.Clone()
+				c.IntersectWith(s2)
+				require.True(t, c.Equal(s1.Intersection(s2)))
+				c = s1.Clone()
+				c.DifferenceWith(s2)
+				require.True(t, c.Equal(s1.Difference(s2)))
+
+				// operands are not modified
+				require.True(t, s1.ToSet().Equal(r1))
+				require.True(t, s2.ToSet().Equal(r2))
+			})
+		}
+	}
+}
+
+func TestIntSet_Equal(t *testing.T) {
+	s1 := IntSetFromArray([]int{1, 500})
+	s2 := IntSetFromArray([]int{1})
+
+	require.False(t, s1.Equal(s2))
+	s1.Remove(500)
+	require.True(t, s1.Equal(s2))
+	require.True(t, s2.Equal(s1))
+}
+
+func TestIntSet_Set(t *testing.T) {
+	set := FromArray([]int{9, 3, 70})
+	s := IntSetFromSet(set)
+
+	require.EqualValues(t, []int{3, 9, 70}, s.Items())
+	require.EqualValues(t, []int{3, 9, 70}, s.ToSet().Items())
+	require.True(t, s.ToSet().Equal(set))
+	require.EqualValues(t, 0, IntSetFromSet(nil).Size())
+	require.Panics(t, func() { IntSetFromSet(FromArray([]int{-1})) })
+}
+
+func TestIntSet_Values(t *testing.T) {
+	s := IntSetFromArray([]int{1, 2, 3, 100})
+	var got []int
+	for e := range s.Values() {
+		if e == 3 {
+			break
+		}
+		got = append(got, e)
+	}
+	require.EqualValues(t, []int{1, 2}, got)
+}
+
+func BenchmarkIntSet_Union(b *testing.B) {
+	s1, s2 := IntSetFromArray(rand.Perm(1e5)[:5e4]), IntSetFromArray(rand.Perm(1e5)[:5e4])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Union(s2)
+	}
+}
+
+func BenchmarkIntSet_Intersection(b *testing.B) {
+	s1, s2 := IntSetFromArray(rand.Perm(1e5)[:5e4]), IntSetFromArray(rand.Perm(1e5)[:5e4])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Intersection(s2)
+	}
+}
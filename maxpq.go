@@ -1,8 +1,20 @@
 package collections
 
 import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math/bits"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrHeapViolation = errors.New("heap property violated")
 )
 
 // Comparator is a function that compares two objects o1 and o2
@@ -13,22 +25,128 @@ import (
 // +ve number if o1 > o2.
 type Comparator[T comparable] func(o1, o2 T) int
 
+// PriorityQueue is the set of operations shared by the max priority
+// queues of this package, so that one can be swapped for another.
+type PriorityQueue[T comparable] interface {
+	Insert(item T)
+	PeekMax() T
+	DelMax() T
+	Size() int
+}
+
 // MaxPQ is an implementation of Max Heap with generics support.
 //
+// The heap is binary by default. NewMaxPQWithOptions creates a d-ary,
+// stable or indexed heap, or any combination of those, as described by
+// MaxPQOptions.
+//
 // The implementation is not thread-safe. For a thread-safe
 // implementation, use ConcurentMaxPQ.
 type MaxPQ[T comparable] struct {
 	items      []T
 	Comparator Comparator[T]
+	d          int
+
+	// tieBreak orders equal elements by their insertion sequence
+	// number, stored in seq at the same index as the element.
+	tieBreak TieBreak
+	seq      []uint64
+	nextSeq  uint64
+
+	// pos maps every element to its index in items when the
+	// collection is indexed, for O(log n) Remove and Fix.
+	pos map[T]int
+
+	// stats is set when statistics are enabled, enq then holds the
+	// time every element was inserted at the same index as the element.
+	stats *pqStats
+	enq   []time.Time
+}
+
+// TieBreak decides the order in which a MaxPQ returns elements
+// that compare as equal.
+type TieBreak int
+
+const (
+	// NoTieBreak returns equal elements in an unspecified order.
+	NoTieBreak TieBreak = iota
+	// FIFO returns equal elements in the order they were inserted.
+	FIFO
+	// LIFO returns equal elements in the reverse order they were inserted.
+	LIFO
+)
+
+// MaxPQOptions configures a MaxPQ created by NewMaxPQWithOptions.
+// The zero value configures the same binary heap as NewMaxPQ.
+type MaxPQOptions struct {
+	// Arity is the number of children of every node. A d-ary heap is
+	// shallower and can be more cache friendly for insert-heavy
+	// workloads. Values less than 2 create a binary heap.
+	Arity int
+	// TieBreak sets the order in which elements that compare as equal
+	// are returned.
+	TieBreak TieBreak
+	// Indexed keeps an index from every element to its position, so
+	// that Remove and Fix run in O(log n). The elements of an indexed
+	// MaxPQ must be unique: inserting an element which is already in
+	// the collection panics.
+	Indexed bool
 }
 
 // Insert inserts a new element in the collection and moves it
 // to the correct position.
 func (pq *MaxPQ[T]) Insert(item T) {
-	pq.items = append(pq.items, item)
+	pq.push(item)
 	pq.swim(len(pq.items) - 1)
 }
 
+// PushAll inserts all the given elements in the collection.
+//
+// Small batches are inserted one at a time, large batches are
+// appended and the heap is rebuilt bottom-up, whichever is cheaper.
+func (pq *MaxPQ[T]) PushAll(items ...T) {
+	if len(items) == 0 {
+		return
+	}
+	n := len(pq.items) + len(items)
+	if pq.pos != nil || len(items)*bits.Len(uint(n)) < n {
+		for _, item := range items {
+			pq.Insert(item)
+		}
+		return
+	}
+	for _, item := range items {
+		pq.push(item)
+	}
+	pq.heapify()
+}
+
+// replaceMax replaces the current max element with the given one
+// and returns the replaced element. It is cheaper than a DelMax
+// followed by an Insert.
+func (pq *MaxPQ[T]) replaceMax(item T) T {
+	top := pq.items[0]
+	pq.items[0] = item
+	if pq.pos != nil {
+		delete(pq.pos, top)
+		pq.pos[item] = 0
+	}
+	if pq.tieBreak != NoTieBreak {
+		pq.seq[0] = pq.nextSeq
+		pq.nextSeq++
+	}
+	if pq.stats != nil {
+		pq.enq[0] = time.Now()
+	}
+	pq.sink(0)
+	return top
+}
+
+// Size returns the number of elements in the collection.
+func (pq *MaxPQ[T]) Size() int {
+	return len(pq.items)
+}
+
 // PeekMax returns the current max/head element.
 func (pq *MaxPQ[T]) PeekMax() T {
 	return pq.items[0]
@@ -38,30 +156,236 @@ func (pq *MaxPQ[T]) PeekMax() T {
 // from the collection.
 func (pq *MaxPQ[T]) DelMax() T {
 	item := pq.items[0]
+	if pq.stats != nil {
+		pq.stats.popped(len(pq.items)-1, time.Since(pq.enq[0]))
+	}
 	pq.exch(0, len(pq.items)-1)
-	pq.items = pq.items[:len(pq.items)-1]
+	pq.truncate(len(pq.items) - 1)
 	pq.sink(0)
 	return item
 }
 
-func (pq *MaxPQ[T]) swim(k int) {
-	for k > 0 && pq.less(k/2, k) {
-		pq.exch(k/2, k)
-		k = k / 2
+// Remove deletes the given element from the collection and returns
+// true if it was present. It runs in O(log n) on an indexed collection
+// and in O(n) otherwise.
+func (pq *MaxPQ[T]) Remove(item T) bool {
+	i := pq.find(item)
+	if i < 0 {
+		return false
 	}
+	last := len(pq.items) - 1
+	pq.exch(i, last)
+	pq.truncate(last)
+	if i < last {
+		pq.fix(i)
+	}
+	if pq.stats != nil {
+		pq.stats.removed(len(pq.items), 1)
+	}
+	return true
 }
 
-func (pq *MaxPQ[T]) sink(k int) {
-	var j int
-	for 2*k <= len(pq.items)-1 {
-		if k == 0 {
-			j = 1
-		} else {
-			j = 2 * k
+// RemoveFunc deletes all the elements for which pred returns true
+// and returns the number of deleted elements.
+func (pq *MaxPQ[T]) RemoveFunc(pred func(item T) bool) int {
+	n := 0
+	for i, item := range pq.items {
+		if pred(item) {
+			if pq.pos != nil {
+				delete(pq.pos, item)
+			}
+			continue
+		}
+		pq.items[n] = item
+		if pq.tieBreak != NoTieBreak {
+			pq.seq[n] = pq.seq[i]
+		}
+		if pq.stats != nil {
+			pq.enq[n] = pq.enq[i]
 		}
+		if pq.pos != nil {
+			pq.pos[item] = n
+		}
+		n++
+	}
+	removed := len(pq.items) - n
+	if removed == 0 {
+		return 0
+	}
+	clear(pq.items[n:])
+	pq.items = pq.items[:n]
+	if pq.tieBreak != NoTieBreak {
+		pq.seq = pq.seq[:n]
+	}
+	if pq.stats != nil {
+		pq.enq = pq.enq[:n]
+		pq.stats.removed(n, removed)
+	}
+	pq.heapify()
+	return removed
+}
+
+// Fix moves the given element to its correct position after its
+// priority has been changed by the caller, which is useful when T is
+// a pointer. It returns false if the element is not in the collection.
+// Fix runs in O(log n) on an indexed collection and in O(n) otherwise.
+func (pq *MaxPQ[T]) Fix(item T) bool {
+	i := pq.find(item)
+	if i < 0 {
+		return false
+	}
+	pq.fix(i)
+	return true
+}
 
-		if j < len(pq.items)-1 && pq.less(j, j+1) {
-			j++
+// find returns the index of the given element, or -1 if it is not
+// in the collection.
+func (pq *MaxPQ[T]) find(item T) int {
+	if pq.pos != nil {
+		if i, ok := pq.pos[item]; ok {
+			return i
+		}
+		return -1
+	}
+	for i := range pq.items {
+		if pq.items[i] == item {
+			return i
+		}
+	}
+	return -1
+}
+
+// fix restores the heap order after the element at index i changed.
+func (pq *MaxPQ[T]) fix(i int) {
+	pq.swim(i)
+	pq.sink(i)
+}
+
+// All returns an iterator over the elements of the collection in
+// heap order, which is unspecified apart from the max element being
+// the first one. The collection must not be modified during iteration.
+func (pq *MaxPQ[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range pq.items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns the elements of the collection in the order DelMax
+// would return them, leaving the collection unchanged.
+func (pq *MaxPQ[T]) Sorted() []T {
+	c := pq.Clone()
+	items := make([]T, 0, len(c.items))
+	for c.Size() > 0 {
+		items = append(items, c.DelMax())
+	}
+	return items
+}
+
+// Drain returns an iterator that deletes and yields the max element
+// until the collection is empty or the iteration is stopped.
+func (pq *MaxPQ[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for pq.Size() > 0 {
+			if !yield(pq.DelMax()) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the collection which can be modified
+// independently of the original. Statistics are not copied.
+func (pq *MaxPQ[T]) Clone() *MaxPQ[T] {
+	c := *pq
+	c.stats, c.enq = nil, nil
+	c.items = make([]T, len(pq.items), cap(pq.items))
+	copy(c.items, pq.items)
+	if pq.seq != nil {
+		c.seq = make([]uint64, len(pq.seq), cap(pq.seq))
+		copy(c.seq, pq.seq)
+	}
+	if pq.pos != nil {
+		c.pos = maps.Clone(pq.pos)
+	}
+	return &c
+}
+
+// Validate checks that every element in the collection is not
+// greater than its parent. It returns an error wrapping ErrHeapViolation
+// for the first element found out of order, nil otherwise.
+func (pq *MaxPQ[T]) Validate() error {
+	for k := 1; k < len(pq.items); k++ {
+		if pq.less(pq.parent(k), k) {
+			return fmt.Errorf("%w: element at %d is greater than its parent at %d",
+				ErrHeapViolation, k, pq.parent(k))
+		}
+	}
+	return nil
+}
+
+// push appends the given element at the end of the heap without
+// restoring the heap order.
+func (pq *MaxPQ[T]) push(item T) {
+	if pq.pos != nil {
+		if _, ok := pq.pos[item]; ok {
+			panic("collections: duplicate element in indexed MaxPQ")
+		}
+		pq.pos[item] = len(pq.items)
+	}
+	pq.items = append(pq.items, item)
+	if pq.tieBreak != NoTieBreak {
+		pq.seq = append(pq.seq, pq.nextSeq)
+		pq.nextSeq++
+	}
+	if pq.stats != nil {
+		pq.enq = append(pq.enq, time.Now())
+		pq.stats.inserted(len(pq.items))
+	}
+}
+
+// truncate drops all the elements from index n onwards.
+func (pq *MaxPQ[T]) truncate(n int) {
+	var zero T
+	if pq.pos != nil {
+		delete(pq.pos, pq.items[n])
+	}
+	pq.items[n] = zero
+	pq.items = pq.items[:n]
+	if pq.tieBreak != NoTieBreak {
+		pq.seq = pq.seq[:n]
+	}
+	if pq.stats != nil {
+		pq.enq = pq.enq[:n]
+	}
+}
+
+// heapify restores the heap order of all the elements in O(n)
+// by sinking every non-leaf element, starting from the last one.
+func (pq *MaxPQ[T]) heapify() {
+	for k := pq.parent(len(pq.items) - 1); k >= 0; k-- {
+		pq.sink(k)
+	}
+}
+
+func (pq *MaxPQ[T]) swim(k int) {
+	for k > 0 && pq.less(pq.parent(k), k) {
+		pq.exch(pq.parent(k), k)
+		k = pq.parent(k)
+	}
+}
+
+func (pq *MaxPQ[T]) sink(k int) {
+	n, d := len(pq.items), pq.arity()
+	for first := d*k + 1; first < n; first = d*k + 1 {
+		j := first
+		for c := first + 1; c < first+d && c < n; c++ {
+			if pq.less(j, c) {
+				j = c
+			}
 		}
 		if !pq.less(k, j) {
 			break
@@ -71,38 +395,94 @@ func (pq *MaxPQ[T]) sink(k int) {
 	}
 }
 
+// parent returns the index of the parent of the element at index k.
+func (pq *MaxPQ[T]) parent(k int) int {
+	return (k - 1) / pq.arity()
+}
+
+// arity returns the number of children of each node in the heap.
+func (pq *MaxPQ[T]) arity() int {
+	if pq.d < 2 {
+		return 2
+	}
+	return pq.d
+}
+
 func (pq *MaxPQ[T]) exch(i, j int) {
 	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	if pq.tieBreak != NoTieBreak {
+		pq.seq[i], pq.seq[j] = pq.seq[j], pq.seq[i]
+	}
+	if pq.stats != nil {
+		pq.enq[i], pq.enq[j] = pq.enq[j], pq.enq[i]
+	}
+	if pq.pos != nil {
+		pq.pos[pq.items[i]] = i
+		pq.pos[pq.items[j]] = j
+	}
 }
 
 func (pq *MaxPQ[T]) less(i, j int) bool {
+	c := pq.compare(pq.items[i], pq.items[j])
+	switch {
+	case c != 0 || pq.tieBreak == NoTieBreak:
+		return c < 0
+	case pq.tieBreak == FIFO:
+		return pq.seq[i] > pq.seq[j]
+	default:
+		return pq.seq[i] < pq.seq[j]
+	}
+}
+
+// compare compares o1 and o2 using the Comparator if one is set,
+// otherwise falls back to comparing primitive values.
+func (pq *MaxPQ[T]) compare(o1, o2 T) int {
 	if pq.Comparator != nil {
-		return pq.Comparator(pq.items[i], pq.items[j]) < 0
+		return pq.Comparator(o1, o2)
 	}
-	switch vi, vj := reflect.ValueOf(pq.items[i]), reflect.ValueOf(pq.items[j]); vi.Kind() {
+	return reflectCompare(o1, o2)
+}
+
+// reflectCompare compares two values of primitive kinds (strings,
+// integers and floats). Values of any other kind compare as equal.
+func reflectCompare[T any](o1, o2 T) int {
+	vi, vj := reflect.ValueOf(o1), reflect.ValueOf(o2)
+	switch vi.Kind() {
 	case reflect.String:
-		return vi.String() < vj.String()
+		return strings.Compare(vi.String(), vj.String())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return vi.Int() < vj.Int()
+		return cmpOrdered(vi.Int(), vj.Int())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return vi.Uint() < vj.Uint()
+		return cmpOrdered(vi.Uint(), vj.Uint())
 	case reflect.Float32, reflect.Float64:
-		return vi.Float() < vj.Float()
+		return cmpOrdered(vi.Float(), vj.Float())
 	default:
-		return false
+		return 0
 	}
 }
 
+func cmpOrdered[T int64 | uint64 | float64](o1, o2 T) int {
+	if o1 < o2 {
+		return -1
+	}
+	if o1 > o2 {
+		return 1
+	}
+	return 0
+}
+
 // ConcurrentMaxPQ is the thread-safe version of MaxPQ.
 // All operations of this type are thread-safe.
 type ConcurrentMaxPQ[T comparable] struct {
 	*MaxPQ[T]
 	mu sync.RWMutex
+	// timed is set once statistics are enabled, to measure lock waits
+	timed atomic.Bool
 }
 
 // Insert inserts the given element in the collection.
 func (pq *ConcurrentMaxPQ[T]) Insert(item T) {
-	pq.mu.Lock()
+	pq.lock()
 	defer pq.mu.Unlock()
 	pq.MaxPQ.Insert(item)
 }
@@ -117,11 +497,115 @@ func (pq *ConcurrentMaxPQ[T]) PeekMax() T {
 // DelMax returns the current max element and deletes it
 // from the collection.
 func (pq *ConcurrentMaxPQ[T]) DelMax() T {
-	pq.mu.Lock()
+	pq.lock()
 	defer pq.mu.Unlock()
 	return pq.MaxPQ.DelMax()
 }
 
+// PushAll inserts all the given elements in the collection.
+func (pq *ConcurrentMaxPQ[T]) PushAll(items ...T) {
+	pq.lock()
+	defer pq.mu.Unlock()
+	pq.MaxPQ.PushAll(items...)
+}
+
+// Size returns the number of elements in the collection.
+func (pq *ConcurrentMaxPQ[T]) Size() int {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return pq.MaxPQ.Size()
+}
+
+// Remove deletes the given element from the collection and returns
+// true if it was present.
+func (pq *ConcurrentMaxPQ[T]) Remove(item T) bool {
+	pq.lock()
+	defer pq.mu.Unlock()
+	return pq.MaxPQ.Remove(item)
+}
+
+// RemoveFunc deletes all the elements for which pred returns true
+// and returns the number of deleted elements.
+func (pq *ConcurrentMaxPQ[T]) RemoveFunc(pred func(item T) bool) int {
+	pq.lock()
+	defer pq.mu.Unlock()
+	return pq.MaxPQ.RemoveFunc(pred)
+}
+
+// Fix moves the given element to its correct position after its
+// priority has been changed by the caller. The change itself must be
+// synchronized with other users of the element by the caller.
+func (pq *ConcurrentMaxPQ[T]) Fix(item T) bool {
+	pq.lock()
+	defer pq.mu.Unlock()
+	return pq.MaxPQ.Fix(item)
+}
+
+// All returns an iterator over a snapshot of the elements taken
+// under the read lock, in heap order.
+func (pq *ConcurrentMaxPQ[T]) All() iter.Seq[T] {
+	pq.mu.RLock()
+	items := make([]T, len(pq.items))
+	copy(items, pq.items)
+	pq.mu.RUnlock()
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns a snapshot of the elements in the order DelMax
+// would return them, leaving the collection unchanged.
+func (pq *ConcurrentMaxPQ[T]) Sorted() []T {
+	pq.mu.RLock()
+	c := pq.MaxPQ.Clone()
+	pq.mu.RUnlock()
+	return c.Sorted()
+}
+
+// Drain returns an iterator that removes the elements from the
+// collection and yields them in priority order. Every element is
+// removed under the lock just before it is yielded, so elements
+// inserted during the iteration are yielded in order with the rest,
+// and the ones not consumed when the iteration is stopped stay in the
+// collection.
+func (pq *ConcurrentMaxPQ[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			pq.lock()
+			if pq.MaxPQ.Size() == 0 {
+				pq.mu.Unlock()
+				return
+			}
+			item := pq.MaxPQ.DelMax()
+			pq.mu.Unlock()
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the collection which can be modified
+// independently of the original.
+func (pq *ConcurrentMaxPQ[T]) Clone() *ConcurrentMaxPQ[T] {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return &ConcurrentMaxPQ[T]{
+		MaxPQ: pq.MaxPQ.Clone(),
+	}
+}
+
+// Validate checks the heap property of the collection.
+func (pq *ConcurrentMaxPQ[T]) Validate() error {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return pq.MaxPQ.Validate()
+}
+
 func NewMaxPQ[T comparable](capacity uint, compareFn Comparator[T]) *MaxPQ[T] {
 	return &MaxPQ[T]{
 		items:      make([]T, 0, capacity),
@@ -137,3 +621,45 @@ func NewConcurrentMaxPQ[T comparable](capacity uint, compareFn Comparator[T]) *C
 		},
 	}
 }
+
+// NewMaxPQWithOptions creates a new MaxPQ configured by opts.
+func NewMaxPQWithOptions[T comparable](capacity uint, compareFn Comparator[T], opts MaxPQOptions) *MaxPQ[T] {
+	pq := NewMaxPQ(capacity, compareFn)
+	pq.d = opts.Arity
+	pq.tieBreak = opts.TieBreak
+	if opts.TieBreak != NoTieBreak {
+		pq.seq = make([]uint64, 0, capacity)
+	}
+	if opts.Indexed {
+		pq.pos = make(map[T]int, capacity)
+	}
+	return pq
+}
+
+// NewConcurrentMaxPQWithOptions creates a new thread-safe MaxPQ
+// configured by opts. See NewMaxPQWithOptions.
+func NewConcurrentMaxPQWithOptions[T comparable](capacity uint, compareFn Comparator[T], opts MaxPQOptions) *ConcurrentMaxPQ[T] {
+	return &ConcurrentMaxPQ[T]{
+		MaxPQ: NewMaxPQWithOptions(capacity, compareFn, opts),
+	}
+}
+
+// NewMaxPQFromSlice creates a new MaxPQ holding a copy of the given
+// elements. The heap is built bottom-up in O(n).
+func NewMaxPQFromSlice[T comparable](items []T, compareFn Comparator[T]) *MaxPQ[T] {
+	elems := make([]T, len(items))
+	copy(elems, items)
+	return Heapify(elems, compareFn)
+}
+
+// Heapify creates a new MaxPQ that takes ownership of the given slice
+// and rearranges it in place into heap order in O(n).
+// The caller must not use the slice after passing it to Heapify.
+func Heapify[T comparable](items []T, compareFn Comparator[T]) *MaxPQ[T] {
+	pq := &MaxPQ[T]{
+		items:      items,
+		Comparator: compareFn,
+	}
+	pq.heapify()
+	return pq
+}
@@ -1,6 +1,11 @@
 package collections
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
 	"sync"
 	"testing"
 
@@ -87,3 +92,400 @@ func TestConcurrentMaxPQ_DelMax(t *testing.T) {
 	require.EqualValues(t, 19, len(pq.items))
 
 }
+
+func TestNewMaxPQFromSlice(t *testing.T) {
+	items := []int{3, 7, 1, 9, 4, 0, 8, 2, 6, 5}
+	pq := NewMaxPQFromSlice(items, nil)
+
+	require.EqualValues(t, 10, pq.Size())
+	require.EqualValues(t, []int{3, 7, 1, 9, 4, 0, 8, 2, 6, 5}, items)
+	for want := 9; want >= 0; want-- {
+		require.EqualValues(t, want, pq.DelMax())
+	}
+}
+
+func TestHeapify(t *testing.T) {
+	items := []temp{{val: 4}, {val: 1}, {val: 9}, {val: 6}}
+	pq := Heapify(items, Compare)
+
+	require.EqualValues(t, temp{val: 9}, items[0])
+	for _, want := range []int{9, 6, 4, 1} {
+		require.EqualValues(t, temp{val: want}, pq.DelMax())
+	}
+	require.EqualValues(t, 0, pq.Size())
+}
+
+func TestMaxPQ_InsertOrder(t *testing.T) {
+	for n := 1; n < 60; n++ {
+		pq := NewMaxPQ[int](0, nil)
+		for _, item := range rand.Perm(n) {
+			pq.Insert(item)
+		}
+		for want := n - 1; want >= 0; want-- {
+			require.EqualValues(t, want, pq.DelMax())
+		}
+	}
+}
+
+func TestMaxPQ_PushAll(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch []int
+		want  []int
+	}{
+		{"small_batch", []int{42}, []int{42, 9, 8, 7}},
+		{"large_batch", []int{15, 11, 19, 13, 10, 17, 12, 18, 14, 16}, []int{19, 18, 17, 16}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pq := setupPQWithInts(t)
+			pq.PushAll(tt.batch...)
+
+			require.EqualValues(t, 10+len(tt.batch), pq.Size())
+			for _, want := range tt.want {
+				require.EqualValues(t, want, pq.DelMax())
+			}
+		})
+	}
+}
+
+func TestMaxPQ_Validate(t *testing.T) {
+	pq := setupPQWithInts(t)
+	require.NoError(t, pq.Validate())
+
+	pq.items[len(pq.items)-1] = 100
+	require.True(t, errors.Is(pq.Validate(), ErrHeapViolation))
+}
+
+func TestMaxPQ_DelMaxRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for n := 0; n < 200; n++ {
+		items := make([]int, n)
+		for i := range items {
+			items[i] = r.Intn(50) - 25
+		}
+		want := make([]int, n)
+		copy(want, items)
+		sort.Slice(want, func(i, j int) bool { return want[i] > want[j] })
+
+		pqs := []*MaxPQ[int]{NewMaxPQFromSlice(items, nil)}
+		for _, d := range []int{2, 3, 4, 8} {
+			pq := NewMaxPQWithOptions[int](0, nil, MaxPQOptions{Arity: d})
+			for _, item := range items {
+				pq.Insert(item)
+				require.NoError(t, pq.Validate())
+			}
+			pqs = append(pqs, pq)
+		}
+
+		for _, pq := range pqs {
+			require.NoError(t, pq.Validate())
+			got := make([]int, 0, n)
+			for pq.Size() > 0 {
+				got = append(got, pq.DelMax())
+				require.NoError(t, pq.Validate())
+			}
+			require.EqualValues(t, want, got)
+		}
+	}
+}
+
+func BenchmarkMaxPQ_InsertHeavy(b *testing.B) {
+	for _, d := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("arity_%d", d), func(b *testing.B) {
+			pq := NewMaxPQWithOptions[int](uint(b.N), nil, MaxPQOptions{Arity: d})
+			r := rand.New(rand.NewSource(1))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pq.Insert(r.Int())
+				if i%8 == 7 {
+					pq.DelMax()
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMaxPQ_PopHeavy(b *testing.B) {
+	const size = 100000
+	for _, d := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("arity_%d", d), func(b *testing.B) {
+			pq := NewMaxPQWithOptions[int](size, nil, MaxPQOptions{Arity: d})
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < size; i++ {
+				pq.Insert(r.Int())
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pq.Insert(pq.DelMax() - r.Intn(1000))
+			}
+		})
+	}
+}
+
+type job struct {
+	name     string
+	priority int
+}
+
+func TestMaxPQ_TieBreak(t *testing.T) {
+	byPriority := func(o1, o2 job) int { return o1.priority - o2.priority }
+	jobs := []job{{"a", 1}, {"b", 2}, {"c", 1}, {"d", 2}, {"e", 1}, {"f", 2}, {"g", 1}}
+	tests := []struct {
+		name     string
+		tieBreak TieBreak
+		want     string
+	}{
+		{"fifo", FIFO, "bdfaceg"},
+		{"lifo", LIFO, "fdbgeca"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pq := NewMaxPQWithOptions(0, byPriority, MaxPQOptions{TieBreak: tt.tieBreak})
+			pq.Insert(jobs[0])
+			pq.PushAll(jobs[1:]...)
+			require.NoError(t, pq.Validate())
+
+			got := ""
+			for pq.Size() > 0 {
+				got += pq.DelMax().name
+			}
+			require.EqualValues(t, tt.want, got)
+		})
+	}
+}
+
+func TestConcurrentMaxPQ_TieBreak(t *testing.T) {
+	pq := NewConcurrentMaxPQWithOptions(0, func(o1, o2 job) int { return o1.priority - o2.priority }, MaxPQOptions{TieBreak: FIFO})
+	for i := 0; i < 20; i++ {
+		pq.Insert(job{name: fmt.Sprint(i), priority: i % 2})
+	}
+	for i := 1; i < 20; i += 2 {
+		require.EqualValues(t, fmt.Sprint(i), pq.DelMax().name)
+	}
+	for i := 0; i < 20; i += 2 {
+		require.EqualValues(t, fmt.Sprint(i), pq.DelMax().name)
+	}
+}
+
+func TestMaxPQ_All(t *testing.T) {
+	pq := setupPQWithInts(t)
+
+	got := make([]int, 0, pq.Size())
+	for item := range pq.All() {
+		got = append(got, item)
+	}
+	require.EqualValues(t, 9, got[0])
+	require.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, got)
+	require.EqualValues(t, 10, pq.Size())
+}
+
+func TestMaxPQ_Sorted(t *testing.T) {
+	pq := setupPQWithStruct(t)
+
+	got := pq.Sorted()
+	require.EqualValues(t, 10, len(got))
+	for i, item := range got {
+		require.EqualValues(t, temp{val: 9 - i}, item)
+	}
+	require.EqualValues(t, 10, pq.Size())
+	require.NoError(t, pq.Validate())
+}
+
+func TestMaxPQ_Drain(t *testing.T) {
+	pq := setupPQWithInts(t)
+
+	want := 9
+	for item := range pq.Drain() {
+		require.EqualValues(t, want, item)
+		if want == 5 {
+			break
+		}
+		want--
+	}
+	require.EqualValues(t, 5, pq.Size())
+	require.EqualValues(t, 4, pq.PeekMax())
+}
+
+func TestMaxPQ_Clone(t *testing.T) {
+	pq := NewMaxPQWithOptions(0, func(o1, o2 job) int { return o1.priority - o2.priority }, MaxPQOptions{TieBreak: FIFO})
+	pq.Insert(job{"a", 1})
+	pq.Insert(job{"b", 1})
+
+	c := pq.Clone()
+	c.Insert(job{"c", 2})
+	require.EqualValues(t, "c", c.DelMax().name)
+	require.EqualValues(t, "a", c.DelMax().name)
+	require.EqualValues(t, 2, pq.Size())
+	require.EqualValues(t, "a", pq.PeekMax().name)
+}
+
+func TestConcurrentMaxPQ_Snapshots(t *testing.T) {
+	pq := NewConcurrentMaxPQ[int](0, nil)
+	pq.PushAll(3, 1, 4, 1, 5, 9, 2, 6)
+
+	c := pq.Clone()
+	c.DelMax()
+	require.EqualValues(t, []int{9, 6, 5, 4, 3, 2, 1, 1}, pq.Sorted())
+	require.EqualValues(t, 8, len(slices.Collect(pq.All())))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		for i := 0; i < 100; i++ {
+			pq.Insert(i % 3)
+		}
+		wg.Done()
+	}()
+	got := 0
+	for range pq.Drain() {
+		got++
+	}
+	wg.Wait()
+	for range pq.Drain() {
+		got++
+	}
+	require.EqualValues(t, 108, got)
+	require.EqualValues(t, 0, pq.Size())
+	require.EqualValues(t, 7, c.Size())
+}
+
+func TestConcurrentMaxPQ_DrainIndexed(t *testing.T) {
+	pq := NewConcurrentMaxPQWithOptions[int](0, nil, MaxPQOptions{Indexed: true})
+	pq.PushAll(1, 2, 3, 4)
+
+	var got []int
+	for item := range pq.Drain() {
+		got = append(got, item)
+		if item == 4 {
+			// the elements waiting to be yielded stay in the collection
+			require.EqualValues(t, 3, pq.Size())
+			require.EqualValues(t, 3, pq.PeekMax())
+			require.True(t, pq.Remove(2))
+			pq.Insert(5)
+		}
+		if item == 3 {
+			break
+		}
+	}
+	require.EqualValues(t, []int{4, 5, 3}, got)
+	require.EqualValues(t, []int{1}, pq.Sorted())
+	require.NoError(t, pq.Validate())
+	require.True(t, pq.Fix(1))
+}
+
+func TestMaxPQ_Remove(t *testing.T) {
+	pqs := map[string]*MaxPQ[int]{
+		"plain":   NewMaxPQ[int](0, nil),
+		"indexed": NewMaxPQWithOptions[int](0, nil, MaxPQOptions{Indexed: true}),
+	}
+	for name, pq := range pqs {
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(11))
+			present := make(map[int]bool)
+			for _, v := range r.Perm(200) {
+				pq.Insert(v)
+				present[v] = true
+			}
+			for i := 0; i < 300; i++ {
+				v := r.Intn(250)
+				require.EqualValues(t, present[v], pq.Remove(v))
+				require.NoError(t, pq.Validate())
+				delete(present, v)
+			}
+			require.EqualValues(t, len(present), pq.Size())
+
+			want := make([]int, 0, len(present))
+			for v := range present {
+				want = append(want, v)
+			}
+			sort.Sort(sort.Reverse(sort.IntSlice(want)))
+			require.EqualValues(t, want, pq.Sorted())
+		})
+	}
+}
+
+func TestMaxPQ_RemoveFunc(t *testing.T) {
+	pq := NewMaxPQWithOptions[int](0, nil, MaxPQOptions{Indexed: true})
+	pq.PushAll(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	require.EqualValues(t, 5, pq.RemoveFunc(func(item int) bool { return item%2 == 1 }))
+	require.EqualValues(t, 0, pq.RemoveFunc(func(item int) bool { return item > 100 }))
+	require.NoError(t, pq.Validate())
+	require.EqualValues(t, []int{8, 6, 4, 2, 0}, pq.Sorted())
+	require.True(t, pq.Remove(4))
+	require.False(t, pq.Remove(5))
+}
+
+func TestMaxPQ_Fix(t *testing.T) {
+	byVal := func(o1, o2 *temp) int { return o1.val - o2.val }
+	items := make([]*temp, 10)
+	pq := NewMaxPQWithOptions(0, byVal, MaxPQOptions{Indexed: true})
+	for i := range items {
+		items[i] = &temp{val: i}
+		pq.Insert(items[i])
+	}
+
+	items[2].val = 42
+	require.True(t, pq.Fix(items[2]))
+	require.EqualValues(t, items[2], pq.PeekMax())
+
+	items[2].val = -1
+	require.True(t, pq.Fix(items[2]))
+	items[9].val = -2
+	require.True(t, pq.Fix(items[9]))
+	require.False(t, pq.Fix(&temp{val: 3}))
+	require.NoError(t, pq.Validate())
+	require.EqualValues(t, items[8], pq.DelMax())
+}
+
+func TestMaxPQ_CombinedOptions(t *testing.T) {
+	byPriority := func(o1, o2 *job) int { return o1.priority - o2.priority }
+	pq := NewMaxPQWithOptions(0, byPriority, MaxPQOptions{Arity: 4, TieBreak: FIFO, Indexed: true})
+	jobs := make([]*job, 20)
+	for i := range jobs {
+		jobs[i] = &job{fmt.Sprint(i), i % 3}
+		pq.Insert(jobs[i])
+	}
+
+	require.True(t, pq.Remove(jobs[2]))
+	jobs[1].priority = 2
+	require.True(t, pq.Fix(jobs[1]))
+	require.NoError(t, pq.Validate())
+
+	// equal priorities come out in insertion order
+	var got []string
+	for pq.Size() > 0 && pq.PeekMax().priority == 2 {
+		got = append(got, pq.DelMax().name)
+	}
+	require.EqualValues(t, []string{"1", "5", "8", "11", "14", "17"}, got)
+}
+
+func TestMaxPQ_IndexedDuplicate(t *testing.T) {
+	pq := NewMaxPQWithOptions[string](0, nil, MaxPQOptions{Indexed: true})
+	pq.Insert("a")
+
+	require.Panics(t, func() { pq.Insert("a") })
+}
+
+func TestConcurrentMaxPQ_Remove(t *testing.T) {
+	pq := NewConcurrentMaxPQWithOptions[int](0, nil, MaxPQOptions{Indexed: true})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i <= 1; i++ {
+		go func(i int) {
+			for j := i * 50; j < i*50+50; j++ {
+				pq.Insert(j)
+				if j%5 == 0 {
+					pq.Remove(j)
+				}
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	require.EqualValues(t, 80, pq.Size())
+	require.EqualValues(t, 40, pq.RemoveFunc(func(item int) bool { return item < 50 }))
+	require.NoError(t, pq.Validate())
+}
@@ -0,0 +1,253 @@
+package collections
+
+import (
+	"fmt"
+	"math/bits"
+	"sync"
+)
+
+// MinMaxPQ is an implementation of a double-ended priority queue
+// backed by a min-max heap. Both the min and the max elements can be
+// read in O(1) and inserted or deleted in O(log n).
+//
+// Elements on even levels of the heap are smaller than all their
+// descendants, elements on odd levels are greater than all their
+// descendants.
+//
+// The implementation is not thread-safe. For a thread-safe
+// implementation, use ConcurrentMinMaxPQ.
+type MinMaxPQ[T comparable] struct {
+	items      []T
+	Comparator Comparator[T]
+}
+
+// Insert inserts a new element in the collection and moves it
+// to the correct position.
+func (pq *MinMaxPQ[T]) Insert(item T) {
+	pq.items = append(pq.items, item)
+	pq.swim(len(pq.items) - 1)
+}
+
+// PeekMin returns the current min element.
+func (pq *MinMaxPQ[T]) PeekMin() T {
+	return pq.items[0]
+}
+
+// PeekMax returns the current max element.
+func (pq *MinMaxPQ[T]) PeekMax() T {
+	return pq.items[pq.maxIndex()]
+}
+
+// DelMin returns the current min element and deletes it
+// from the collection.
+func (pq *MinMaxPQ[T]) DelMin() T {
+	return pq.delete(0)
+}
+
+// DelMax returns the current max element and deletes it
+// from the collection.
+func (pq *MinMaxPQ[T]) DelMax() T {
+	return pq.delete(pq.maxIndex())
+}
+
+// Size returns the number of elements in the collection.
+func (pq *MinMaxPQ[T]) Size() int {
+	return len(pq.items)
+}
+
+// Validate checks that every element is ordered with respect to all
+// its ancestors. It returns an error wrapping ErrHeapViolation for the
+// first element found out of order, nil otherwise.
+func (pq *MinMaxPQ[T]) Validate() error {
+	for k := 1; k < len(pq.items); k++ {
+		for a := (k - 1) / 2; ; a = (a - 1) / 2 {
+			c := pq.compare(pq.items[k], pq.items[a])
+			if (isMinLevel(a) && c < 0) || (!isMinLevel(a) && c > 0) {
+				return fmt.Errorf("%w: element at %d is out of order with its ancestor at %d",
+					ErrHeapViolation, k, a)
+			}
+			if a == 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// maxIndex returns the index of the max element, which is
+// one of the children of the root.
+func (pq *MinMaxPQ[T]) maxIndex() int {
+	switch len(pq.items) {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if pq.less(1, 2) {
+		return 2
+	}
+	return 1
+}
+
+// delete removes the element at index i, which must be either
+// the min or the max element, and returns it.
+func (pq *MinMaxPQ[T]) delete(i int) T {
+	item := pq.items[i]
+	last := len(pq.items) - 1
+	pq.exch(i, last)
+	var zero T
+	pq.items[last] = zero
+	pq.items = pq.items[:last]
+	if i < last {
+		pq.sink(i)
+	}
+	return item
+}
+
+func (pq *MinMaxPQ[T]) swim(k int) {
+	if k == 0 {
+		return
+	}
+	p := (k - 1) / 2
+	minLevel := isMinLevel(k)
+	if pq.before(p, k, minLevel) {
+		pq.exch(p, k)
+		k = p
+		minLevel = !minLevel
+	}
+	// move up through the grandparents, which are on the same kind of level
+	for k > 2 {
+		g := ((k-1)/2 - 1) / 2
+		if !pq.before(k, g, minLevel) {
+			return
+		}
+		pq.exch(k, g)
+		k = g
+	}
+}
+
+func (pq *MinMaxPQ[T]) sink(k int) {
+	minLevel := isMinLevel(k)
+	n := len(pq.items)
+	for 2*k+1 < n {
+		// m is the smallest (or largest on max levels) of the
+		// children and grandchildren of k
+		m := 2*k + 1
+		for _, c := range [...]int{2*k + 2, 4*k + 3, 4*k + 4, 4*k + 5, 4*k + 6} {
+			if c < n && pq.before(c, m, minLevel) {
+				m = c
+			}
+		}
+		if !pq.before(m, k, minLevel) {
+			return
+		}
+		pq.exch(m, k)
+		if m <= 2*k+2 {
+			return
+		}
+		if p := (m - 1) / 2; pq.before(p, m, minLevel) {
+			pq.exch(p, m)
+		}
+		k = m
+	}
+}
+
+// before returns true if the element at i must be placed above the
+// element at j on a min level (when minLevel is true) or a max level.
+func (pq *MinMaxPQ[T]) before(i, j int, minLevel bool) bool {
+	if minLevel {
+		return pq.less(i, j)
+	}
+	return pq.less(j, i)
+}
+
+// isMinLevel returns true if the element at index k is on a min level.
+func isMinLevel(k int) bool {
+	return bits.Len(uint(k+1))%2 == 1
+}
+
+func (pq *MinMaxPQ[T]) exch(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+}
+
+func (pq *MinMaxPQ[T]) less(i, j int) bool {
+	return pq.compare(pq.items[i], pq.items[j]) < 0
+}
+
+func (pq *MinMaxPQ[T]) compare(o1, o2 T) int {
+	if pq.Comparator != nil {
+		return pq.Comparator(o1, o2)
+	}
+	return reflectCompare(o1, o2)
+}
+
+// ConcurrentMinMaxPQ is the thread-safe version of MinMaxPQ.
+// All operations of this type are thread-safe.
+type ConcurrentMinMaxPQ[T comparable] struct {
+	*MinMaxPQ[T]
+	mu sync.RWMutex
+}
+
+// Insert inserts the given element in the collection.
+func (pq *ConcurrentMinMaxPQ[T]) Insert(item T) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	pq.MinMaxPQ.Insert(item)
+}
+
+// PeekMin returns the current min element.
+func (pq *ConcurrentMinMaxPQ[T]) PeekMin() T {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return pq.MinMaxPQ.PeekMin()
+}
+
+// PeekMax returns the current max element.
+func (pq *ConcurrentMinMaxPQ[T]) PeekMax() T {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return pq.MinMaxPQ.PeekMax()
+}
+
+// DelMin returns the current min element and deletes it
+// from the collection.
+func (pq *ConcurrentMinMaxPQ[T]) DelMin() T {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	return pq.MinMaxPQ.DelMin()
+}
+
+// DelMax returns the current max element and deletes it
+// from the collection.
+func (pq *ConcurrentMinMaxPQ[T]) DelMax() T {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	return pq.MinMaxPQ.DelMax()
+}
+
+// Size returns the number of elements in the collection.
+func (pq *ConcurrentMinMaxPQ[T]) Size() int {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return pq.MinMaxPQ.Size()
+}
+
+// Validate checks the heap property of the collection.
+func (pq *ConcurrentMinMaxPQ[T]) Validate() error {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return pq.MinMaxPQ.Validate()
+}
+
+func NewMinMaxPQ[T comparable](capacity uint, compareFn Comparator[T]) *MinMaxPQ[T] {
+	return &MinMaxPQ[T]{
+		items:      make([]T, 0, capacity),
+		Comparator: compareFn,
+	}
+}
+
+func NewConcurrentMinMaxPQ[T comparable](capacity uint, compareFn Comparator[T]) *ConcurrentMinMaxPQ[T] {
+	return &ConcurrentMinMaxPQ[T]{
+		MinMaxPQ: NewMinMaxPQ(capacity, compareFn),
+	}
+}
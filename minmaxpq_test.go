@@ -0,0 +1,84 @@
+package collections
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinMaxPQ_Peek(t *testing.T) {
+	pq := NewMinMaxPQ[int](10, nil)
+	for _, v := range []int{5, 3, 8, 1, 9, 2} {
+		pq.Insert(v)
+	}
+
+	require.EqualValues(t, 6, pq.Size())
+	require.EqualValues(t, 1, pq.PeekMin())
+	require.EqualValues(t, 9, pq.PeekMax())
+}
+
+func TestMinMaxPQ_DelMinDelMax(t *testing.T) {
+	pq := NewMinMaxPQ(10, Compare)
+	for i := 0; i < 10; i++ {
+		pq.Insert(temp{val: i})
+	}
+
+	require.EqualValues(t, temp{val: 9}, pq.DelMax())
+	require.EqualValues(t, temp{val: 0}, pq.DelMin())
+	require.EqualValues(t, temp{val: 8}, pq.DelMax())
+	require.EqualValues(t, temp{val: 1}, pq.DelMin())
+	require.EqualValues(t, 6, pq.Size())
+	require.NoError(t, pq.Validate())
+}
+
+func TestMinMaxPQ_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for n := 1; n < 100; n++ {
+		pq := NewMinMaxPQ[int](0, nil)
+		want := make([]int, 0, n)
+		for i := 0; i < n; i++ {
+			v := r.Intn(50)
+			pq.Insert(v)
+			want = append(want, v)
+			require.NoError(t, pq.Validate())
+		}
+		sort.Ints(want)
+
+		for len(want) > 0 {
+			if r.Intn(2) == 0 {
+				require.EqualValues(t, want[0], pq.DelMin())
+				want = want[1:]
+			} else {
+				require.EqualValues(t, want[len(want)-1], pq.DelMax())
+				want = want[:len(want)-1]
+			}
+			require.NoError(t, pq.Validate())
+		}
+		require.EqualValues(t, 0, pq.Size())
+	}
+}
+
+func TestConcurrentMinMaxPQ_DelMin(t *testing.T) {
+	pq := NewConcurrentMinMaxPQ[int](20, nil)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i <= 1; i++ {
+		go func(i int) {
+			for j := i * 10; j < i*10+10; j++ {
+				pq.Insert(j)
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	require.EqualValues(t, 20, pq.Size())
+	require.EqualValues(t, 0, pq.DelMin())
+	require.EqualValues(t, 19, pq.DelMax())
+	require.EqualValues(t, 1, pq.PeekMin())
+	require.EqualValues(t, 18, pq.PeekMax())
+	require.NoError(t, pq.Validate())
+}
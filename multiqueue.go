@@ -0,0 +1,123 @@
+package collections
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// MultiQueue is a relaxed concurrent max priority queue for workloads
+// with many concurrent producers and consumers, where a single lock
+// like the one of ConcurrentMaxPQ becomes the bottleneck.
+//
+// Elements are spread over several shards, each one a MaxPQ with its
+// own lock. Insert adds to a random shard and DelMax picks two random
+// shards and deletes the larger of their max elements.
+//
+// Ordering guarantees are relaxed: DelMax returns one of the largest
+// elements with high probability, but not necessarily the largest one.
+// Elements of a single shard are always returned in priority order and
+// a MultiQueue with a single shard behaves like ConcurrentMaxPQ.
+// DelMax never returns false while the queue holds elements that were
+// inserted before the call started.
+//
+// All operations of this type are thread-safe.
+type MultiQueue[T comparable] struct {
+	shards []mqShard[T]
+	size   atomic.Int64
+}
+
+type mqShard[T comparable] struct {
+	mu sync.Mutex
+	pq *MaxPQ[T]
+	// pad to a cache line to avoid false sharing between shards
+	_ [48]byte
+}
+
+// Insert inserts the given element in a random shard.
+func (q *MultiQueue[T]) Insert(item T) {
+	s := q.lockRandom()
+	s.pq.Insert(item)
+	q.size.Add(1)
+	s.mu.Unlock()
+}
+
+// DelMax deletes and returns the larger of the max elements of two
+// random shards. It returns the zero value and false if the queue
+// is empty.
+func (q *MultiQueue[T]) DelMax() (T, bool) {
+	if q.size.Load() > 0 {
+		i, j := rand.IntN(len(q.shards)), rand.IntN(len(q.shards))
+		if i > j {
+			i, j = j, i
+		}
+		s1, s2 := &q.shards[i], &q.shards[j]
+		s1.mu.Lock()
+		if s2 != s1 {
+			s2.mu.Lock()
+			if s1.pq.Size() == 0 || (s2.pq.Size() > 0 && s1.pq.compare(s1.pq.PeekMax(), s2.pq.PeekMax()) < 0) {
+				s1, s2 = s2, s1
+			}
+			s2.mu.Unlock()
+		}
+		if s1.pq.Size() > 0 {
+			item := s1.pq.DelMax()
+			q.size.Add(-1)
+			s1.mu.Unlock()
+			return item, true
+		}
+		s1.mu.Unlock()
+	}
+	// both picked shards were empty, fall back to looking at every shard
+	for i := range q.shards {
+		if q.size.Load() == 0 {
+			break
+		}
+		s := &q.shards[i]
+		s.mu.Lock()
+		if s.pq.Size() > 0 {
+			item := s.pq.DelMax()
+			q.size.Add(-1)
+			s.mu.Unlock()
+			return item, true
+		}
+		s.mu.Unlock()
+	}
+	var zero T
+	return zero, false
+}
+
+// Size returns the number of elements in the queue.
+func (q *MultiQueue[T]) Size() int {
+	return int(q.size.Load())
+}
+
+// lockRandom locks and returns a random shard, preferring
+// shards that are not locked by other goroutines.
+func (q *MultiQueue[T]) lockRandom() *mqShard[T] {
+	for attempt := 0; attempt < len(q.shards); attempt++ {
+		s := &q.shards[rand.IntN(len(q.shards))]
+		if s.mu.TryLock() {
+			return s
+		}
+	}
+	s := &q.shards[rand.IntN(len(q.shards))]
+	s.mu.Lock()
+	return s
+}
+
+// NewMultiQueue creates a new MultiQueue with the given number of shards.
+// If shards is less than 1, twice the value of GOMAXPROCS is used.
+func NewMultiQueue[T comparable](shards int, compareFn Comparator[T]) *MultiQueue[T] {
+	if shards < 1 {
+		shards = 2 * runtime.GOMAXPROCS(0)
+	}
+	q := &MultiQueue[T]{
+		shards: make([]mqShard[T], shards),
+	}
+	for i := range q.shards {
+		q.shards[i].pq = NewMaxPQ[T](0, compareFn)
+	}
+	return q
+}
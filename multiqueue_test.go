@@ -0,0 +1,120 @@
+package collections
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiQueue_DelMax(t *testing.T) {
+	q := NewMultiQueue[int](4, nil)
+	for i := 0; i < 100; i++ {
+		q.Insert(i)
+	}
+	require.EqualValues(t, 100, q.Size())
+
+	got := make([]int, 0, 100)
+	for {
+		item, ok := q.DelMax()
+		if !ok {
+			break
+		}
+		got = append(got, item)
+	}
+	require.EqualValues(t, 0, q.Size())
+	sort.Ints(got)
+	for i := range got {
+		require.EqualValues(t, i, got[i])
+	}
+}
+
+func TestMultiQueue_SingleShard(t *testing.T) {
+	q := NewMultiQueue(1, Compare)
+	for _, v := range []int{4, 8, 1, 6} {
+		q.Insert(temp{val: v})
+	}
+
+	for _, want := range []int{8, 6, 4, 1} {
+		item, ok := q.DelMax()
+		require.True(t, ok)
+		require.EqualValues(t, temp{val: want}, item)
+	}
+	_, ok := q.DelMax()
+	require.False(t, ok)
+}
+
+func TestMultiQueue_Concurrent(t *testing.T) {
+	q := NewMultiQueue[int](0, nil)
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		got = make(map[int]bool)
+	)
+	wg.Add(16)
+	for i := 0; i < 16; i++ {
+		go func(i int) {
+			defer wg.Done()
+			for j := i * 100; j < i*100+100; j++ {
+				q.Insert(j)
+				// DelMax can return false even after an own Insert,
+				// as other goroutines may have deleted that element
+				if j%2 == 0 {
+					if item, ok := q.DelMax(); ok {
+						mu.Lock()
+						got[item] = true
+						mu.Unlock()
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for item, ok := q.DelMax(); ok; item, ok = q.DelMax() {
+		got[item] = true
+	}
+	require.EqualValues(t, 1600, len(got))
+}
+
+func BenchmarkPQ_Contention(b *testing.B) {
+	const prefill = 10000
+	queues := []struct {
+		name string
+		new  func() (insert func(int), delMax func())
+	}{
+		{"ConcurrentMaxPQ", func() (func(int), func()) {
+			pq := NewConcurrentMaxPQ[int](prefill, nil)
+			return pq.Insert, func() { pq.DelMax() }
+		}},
+		{"MultiQueue", func() (func(int), func()) {
+			q := NewMultiQueue[int](0, nil)
+			return q.Insert, func() { q.DelMax() }
+		}},
+	}
+	for _, queue := range queues {
+		for _, parallelism := range []int{1, 8, 32} {
+			// RunParallel starts parallelism*GOMAXPROCS goroutines
+			goroutines := parallelism * runtime.GOMAXPROCS(0)
+			b.Run(fmt.Sprintf("%s/goroutines_%d", queue.name, goroutines), func(b *testing.B) {
+				insert, delMax := queue.new()
+				for i := 0; i < prefill; i++ {
+					insert(i)
+				}
+				b.SetParallelism(parallelism)
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					i := 0
+					for pb.Next() {
+						insert(i)
+						delMax()
+						i++
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package collections
+
+// Multiset provides a collection which counts how many times every
+// element was added to it, also known as a bag.
+//
+// Union and Intersection return new multisets and never modify their
+// operands: an element is counted in the union as many times as in the
+// operand where it is the most common, and in the intersection as many
+// times as in the operand where it is the least common. A nil *Multiset
+// is treated as an empty multiset by all the methods that do not
+// modify it.
+//
+// The order of the elements is unspecified.
+//
+// The implementation is not thread-safe.
+type Multiset[T comparable] struct {
+	counts map[T]int
+	total  int
+}
+
+// ElementCount is an element of a Multiset with its count.
+type ElementCount[T comparable] struct {
+	Elem  T
+	Count int
+}
+
+// Add adds n occurrences of the given element to the multiset.
+// It is a no-op if n is not positive.
+func (m *Multiset[T]) Add(e T, n int) {
+	if n <= 0 {
+		return
+	}
+	if m.counts == nil {
+		m.counts = make(map[T]int)
+	}
+	m.counts[e] += n
+	m.total += n
+}
+
+// Remove removes up to n occurrences of the given element from the
+// multiset and returns the number of removed occurrences.
+func (m *Multiset[T]) Remove(e T, n int) int {
+	c := m.Count(e)
+	if n <= 0 || c == 0 {
+		return 0
+	}
+	if n >= c {
+		delete(m.counts, e)
+		n = c
+	} else {
+		m.counts[e] = c - n
+	}
+	m.total -= n
+	return n
+}
+
+// Count returns the number of occurrences of the given element.
+func (m *Multiset[T]) Count(e T) int {
+	if m == nil {
+		return 0
+	}
+	return m.counts[e]
+}
+
+// Contains returns true if the given element
+// occurs at least once in the multiset.
+func (m *Multiset[T]) Contains(e T) bool {
+	return m.Count(e) > 0
+}
+
+// Size returns the number of distinct elements in the multiset.
+func (m *Multiset[T]) Size() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.counts)
+}
+
+// TotalSize returns the number of occurrences of all the elements.
+func (m *Multiset[T]) TotalSize() int {
+	if m == nil {
+		return 0
+	}
+	return m.total
+}
+
+// Distinct returns a new set with the distinct elements of the multiset.
+func (m *Multiset[T]) Distinct() *Set[T] {
+	s := NewSet[T](m.Size())
+	if m == nil {
+		return s
+	}
+	for e := range m.counts {
+		s.Add(e)
+	}
+	return s
+}
+
+// MostCommon returns the k most common elements with their counts,
+// sorted by descending count, or all of them if there are fewer than k.
+// The order of elements with the same count is unspecified.
+// It runs in O(n log k) for n distinct elements.
+func (m *Multiset[T]) MostCommon(k int) []ElementCount[T] {
+	if k > m.Size() {
+		k = m.Size()
+	}
+	top := NewTopK(k, func(o1, o2 ElementCount[T]) int {
+		return o1.Count - o2.Count
+	})
+	if m != nil {
+		for e, c := range m.counts {
+			top.Offer(ElementCount[T]{Elem: e, Count: c})
+		}
+	}
+	return top.Items()
+}
+
+// Clone returns a copy of the multiset which can be modified
+// independently of the original.
+func (m *Multiset[T]) Clone() *Multiset[T] {
+	c := NewMultiset[T](m.Size())
+	if m == nil {
+		return c
+	}
+	for e, n := range m.counts {
+		c.counts[e] = n
+	}
+	c.total = m.total
+	return c
+}
+
+// Union returns a new multiset where every element is counted as many
+// times as in the operand where it is the most common.
+func (m *Multiset[T]) Union(m2 *Multiset[T]) *Multiset[T] {
+	u := m.Clone()
+	if m2 == nil {
+		return u
+	}
+	for e, n := range m2.counts {
+		if c := u.counts[e]; n > c {
+			u.Add(e, n-c)
+		}
+	}
+	return u
+}
+
+// Intersection returns a new multiset where every element is counted as
+// many times as in the operand where it is the least common.
+func (m *Multiset[T]) Intersection(m2 *Multiset[T]) *Multiset[T] {
+	common := NewMultiset[T](0)
+	if m == nil || m2 == nil {
+		return common
+	}
+	o1, o2 := m, m2
+	if o1.Size() > o2.Size() {
+		o1, o2 = o2, o1
+	}
+	for e, n := range o1.counts {
+		if c := o2.counts[e]; c < n {
+			n = c
+		}
+		common.Add(e, n)
+	}
+	return common
+}
+
+// NewMultiset creates and returns a new multiset with the given initial
+// capacity of distinct elements.
+func NewMultiset[T comparable](initialSize int) *Multiset[T] {
+	if initialSize < 0 {
+		initialSize = 0
+	}
+	return &Multiset[T]{
+		counts: make(map[T]int, initialSize),
+	}
+}
+
+// MultisetFromArray creates a new multiset counting the elements
+// of the given array/slice.
+func MultisetFromArray[T comparable](a []T) *Multiset[T] {
+	m := NewMultiset[T](0)
+	for _, e := range a {
+		m.Add(e, 1)
+	}
+	return m
+}
@@ -0,0 +1,97 @@
+package collections
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiset_AddRemove(t *testing.T) {
+	m := NewMultiset[string](0)
+	m.Add("go", 3)
+	m.Add("rust", 1)
+	m.Add("go", 2)
+	m.Add("zig", 0)
+	m.Add("zig", -1)
+
+	require.EqualValues(t, 5, m.Count("go"))
+	require.EqualValues(t, 0, m.Count("zig"))
+	require.False(t, m.Contains("zig"))
+	require.EqualValues(t, 2, m.Size())
+	require.EqualValues(t, 6, m.TotalSize())
+
+	require.EqualValues(t, 2, m.Remove("go", 2))
+	require.EqualValues(t, 3, m.Count("go"))
+	require.EqualValues(t, 1, m.Remove("rust", 5))
+	require.False(t, m.Contains("rust"))
+	require.EqualValues(t, 0, m.Remove("rust", 1))
+	require.EqualValues(t, 0, m.Remove("go", -1))
+	require.EqualValues(t, 1, m.Size())
+	require.EqualValues(t, 3, m.TotalSize())
+}
+
+func TestMultiset_ZeroValue(t *testing.T) {
+	var m Multiset[int]
+	m.Add(1, 2)
+	require.EqualValues(t, 2, m.Count(1))
+
+	var nilSet *Multiset[int]
+	require.EqualValues(t, 0, nilSet.Count(1))
+	require.EqualValues(t, 0, nilSet.TotalSize())
+	require.EqualValues(t, 0, nilSet.Distinct().Size())
+	require.Empty(t, nilSet.MostCommon(3))
+	require.EqualValues(t, 2, nilSet.Union(&m).Count(1))
+}
+
+func TestMultiset_Distinct(t *testing.T) {
+	m := MultisetFromArray([]int{3, 1, 3, 2, 3})
+	got := m.Distinct()
+
+	require.True(t, got.Equal(FromArray([]int{1, 2, 3})))
+}
+
+func TestMultiset_MostCommon(t *testing.T) {
+	m := MultisetFromArray(strings.Fields("a b c a b a d d d d"))
+
+	require.EqualValues(t, []ElementCount[string]{{"d", 4}, {"a", 3}}, m.MostCommon(2))
+	require.EqualValues(t, []ElementCount[string]{{"d", 4}, {"a", 3}, {"b", 2}, {"c", 1}}, m.MostCommon(10))
+	require.Empty(t, m.MostCommon(0))
+	require.EqualValues(t, m.MostCommon(4), m.MostCommon(math.MaxInt))
+}
+
+func TestMultiset_Operations(t *testing.T) {
+	m1 := MultisetFromArray([]string{"a", "a", "a", "b", "c"})
+	m2 := MultisetFromArray([]string{"a", "b", "b", "d"})
+
+	u := m1.Union(m2)
+	require.EqualValues(t, 3, u.Count("a"))
+	require.EqualValues(t, 2, u.Count("b"))
+	require.EqualValues(t, 1, u.Count("c"))
+	require.EqualValues(t, 1, u.Count("d"))
+	require.EqualValues(t, 7, u.TotalSize())
+
+	i := m1.Intersection(m2)
+	require.EqualValues(t, 1, i.Count("a"))
+	require.EqualValues(t, 1, i.Count("b"))
+	require.False(t, i.Contains("c"))
+	require.False(t, i.Contains("d"))
+	require.EqualValues(t, 2, i.Size())
+	require.EqualValues(t, 2, i.TotalSize())
+
+	// operands are not modified
+	require.EqualValues(t, 5, m1.TotalSize())
+	require.EqualValues(t, 4, m2.TotalSize())
+	require.EqualValues(t, 0, m1.Intersection(nil).TotalSize())
+}
+
+func TestMultiset_Clone(t *testing.T) {
+	m := MultisetFromArray([]int{1, 1, 2})
+	c := m.Clone()
+	c.Remove(1, 2)
+
+	require.EqualValues(t, 2, m.Count(1))
+	require.EqualValues(t, 3, m.TotalSize())
+	require.EqualValues(t, 1, c.TotalSize())
+}
@@ -0,0 +1,188 @@
+package collections
+
+// PairingNode is an element stored in a PairingHeap. It is returned by
+// PairingHeap.InsertNode and acts as a handle to the element for
+// Update and Delete.
+type PairingNode[T comparable] struct {
+	item    T
+	child   *PairingNode[T]
+	sibling *PairingNode[T]
+	// prev is the parent for the leftmost child, otherwise the left sibling.
+	prev *PairingNode[T]
+}
+
+// Item returns the element held by the node.
+func (n *PairingNode[T]) Item() T {
+	return n.item
+}
+
+// PairingHeap is a mergeable max heap. Insert, PeekMax and Meld run
+// in O(1) and DelMax in O(log n) amortized time.
+//
+// It has the same methods as MaxPQ and can be used in its place
+// where two heaps need to be combined cheaply.
+//
+// The implementation is not thread-safe.
+type PairingHeap[T comparable] struct {
+	root       *PairingNode[T]
+	size       int
+	Comparator Comparator[T]
+}
+
+// Insert inserts a new element in the collection.
+func (h *PairingHeap[T]) Insert(item T) {
+	h.InsertNode(item)
+}
+
+// InsertNode inserts a new element in the collection and returns its node,
+// which can be passed to Update and Delete while the element is in the heap.
+func (h *PairingHeap[T]) InsertNode(item T) *PairingNode[T] {
+	n := &PairingNode[T]{item: item}
+	h.root = h.link(h.root, n)
+	h.size++
+	return n
+}
+
+// PeekMax returns the current max/head element.
+func (h *PairingHeap[T]) PeekMax() T {
+	return h.root.item
+}
+
+// DelMax returns the current max element and deletes it
+// from the collection.
+func (h *PairingHeap[T]) DelMax() T {
+	n := h.root
+	h.root = h.mergePairs(n.child)
+	n.child = nil
+	h.size--
+	return n.item
+}
+
+// Meld moves all the elements of other into the current heap in O(1),
+// leaving other empty. Elements are ordered by the Comparator of the
+// current heap, which should be the same as the one of other.
+func (h *PairingHeap[T]) Meld(other *PairingHeap[T]) {
+	if other == nil || other == h {
+		return
+	}
+	h.root = h.link(h.root, other.root)
+	h.size += other.size
+	other.root = nil
+	other.size = 0
+}
+
+// Update replaces the element held by the given node and moves it to
+// its new position. Raising the priority of an element (the max heap
+// counterpart of DecreaseKey) runs in O(1), lowering it in O(log n)
+// amortized time.
+func (h *PairingHeap[T]) Update(n *PairingNode[T], item T) {
+	old := n.item
+	n.item = item
+	if h.compare(item, old) >= 0 {
+		if n != h.root {
+			h.cut(n)
+			h.root = h.link(h.root, n)
+		}
+		return
+	}
+	h.Delete(n)
+	h.root = h.link(h.root, n)
+	h.size++
+}
+
+// Delete removes the element held by the given node from the collection.
+func (h *PairingHeap[T]) Delete(n *PairingNode[T]) {
+	if n == h.root {
+		h.DelMax()
+		return
+	}
+	h.cut(n)
+	h.root = h.link(h.root, h.mergePairs(n.child))
+	n.child = nil
+	h.size--
+}
+
+// Size returns the number of elements in the collection.
+func (h *PairingHeap[T]) Size() int {
+	return h.size
+}
+
+// link makes the smaller of the two given roots the leftmost child
+// of the other one and returns the new root.
+func (h *PairingHeap[T]) link(a, b *PairingNode[T]) *PairingNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.compare(a.item, b.item) < 0 {
+		a, b = b, a
+	}
+	b.sibling = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	b.prev = a
+	a.child = b
+	a.sibling = nil
+	a.prev = nil
+	return a
+}
+
+// cut detaches the subtree rooted at n from its parent.
+func (h *PairingHeap[T]) cut(n *PairingNode[T]) {
+	if n.prev.child == n {
+		n.prev.child = n.sibling
+	} else {
+		n.prev.sibling = n.sibling
+	}
+	if n.sibling != nil {
+		n.sibling.prev = n.prev
+	}
+	n.prev = nil
+	n.sibling = nil
+}
+
+// mergePairs merges the list of siblings starting at first into a
+// single tree using the standard two-pass pairing and returns its root.
+func (h *PairingHeap[T]) mergePairs(first *PairingNode[T]) *PairingNode[T] {
+	// first pass: link pairs left to right, stacking the results
+	var pairs *PairingNode[T]
+	for first != nil {
+		a, b := first, first.sibling
+		first = nil
+		if b != nil {
+			first = b.sibling
+			b.sibling, b.prev = nil, nil
+		}
+		a.sibling, a.prev = nil, nil
+		m := h.link(a, b)
+		m.sibling = pairs
+		pairs = m
+	}
+
+	// second pass: link the stacked pairs right to left
+	var root *PairingNode[T]
+	for pairs != nil {
+		next := pairs.sibling
+		pairs.sibling = nil
+		root = h.link(root, pairs)
+		pairs = next
+	}
+	return root
+}
+
+func (h *PairingHeap[T]) compare(o1, o2 T) int {
+	if h.Comparator != nil {
+		return h.Comparator(o1, o2)
+	}
+	return reflectCompare(o1, o2)
+}
+
+// NewPairingHeap creates a new empty PairingHeap.
+func NewPairingHeap[T comparable](compareFn Comparator[T]) *PairingHeap[T] {
+	return &PairingHeap[T]{
+		Comparator: compareFn,
+	}
+}
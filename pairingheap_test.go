@@ -0,0 +1,99 @@
+package collections
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ PriorityQueue[int] = (*MaxPQ[int])(nil)
+	_ PriorityQueue[int] = (*ConcurrentMaxPQ[int])(nil)
+	_ PriorityQueue[int] = (*PairingHeap[int])(nil)
+)
+
+func TestPairingHeap_DelMax(t *testing.T) {
+	h := NewPairingHeap[int](nil)
+	for _, v := range []int{5, 2, 8, 1, 9, 3} {
+		h.Insert(v)
+	}
+
+	require.EqualValues(t, 6, h.Size())
+	require.EqualValues(t, 9, h.PeekMax())
+	for _, want := range []int{9, 8, 5, 3, 2, 1} {
+		require.EqualValues(t, want, h.DelMax())
+	}
+	require.EqualValues(t, 0, h.Size())
+}
+
+func TestPairingHeap_Meld(t *testing.T) {
+	h1 := NewPairingHeap(Compare)
+	h2 := NewPairingHeap(Compare)
+	for i := 0; i < 10; i++ {
+		h1.Insert(temp{val: 2 * i})
+		h2.Insert(temp{val: 2*i + 1})
+	}
+	h1.Meld(h2)
+
+	require.EqualValues(t, 20, h1.Size())
+	require.EqualValues(t, 0, h2.Size())
+	for want := 19; want >= 0; want-- {
+		require.EqualValues(t, temp{val: want}, h1.DelMax())
+	}
+}
+
+func TestPairingHeap_Update(t *testing.T) {
+	h := NewPairingHeap[int](nil)
+	nodes := make([]*PairingNode[int], 0, 10)
+	for i := 0; i < 10; i++ {
+		nodes = append(nodes, h.InsertNode(i))
+	}
+	h.DelMax()
+
+	h.Update(nodes[3], 100)
+	require.EqualValues(t, 100, h.PeekMax())
+
+	h.Update(nodes[3], -1)
+	h.Update(nodes[8], 0)
+	h.Delete(nodes[5])
+	require.EqualValues(t, 8, h.Size())
+	for _, want := range []int{7, 6, 4, 2, 1, 0, 0, -1} {
+		require.EqualValues(t, want, h.DelMax())
+	}
+}
+
+func TestPairingHeap_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	h := NewPairingHeap[int](nil)
+	var want []int
+	nodes := make(map[*PairingNode[int]]struct{})
+	for i := 0; i < 2000; i++ {
+		switch op := r.Intn(4); {
+		case op < 2 || h.Size() == 0:
+			nodes[h.InsertNode(r.Intn(1000))] = struct{}{}
+		case op == 2:
+			for n := range nodes {
+				h.Update(n, r.Intn(1000))
+				break
+			}
+		default:
+			for n := range nodes {
+				h.Delete(n)
+				delete(nodes, n)
+				break
+			}
+		}
+	}
+	for n := range nodes {
+		want = append(want, n.Item())
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(want)))
+
+	got := make([]int, 0, len(want))
+	for h.Size() > 0 {
+		got = append(got, h.DelMax())
+	}
+	require.EqualValues(t, want, got)
+}
@@ -0,0 +1,63 @@
+package collections
+
+import "context"
+
+// StageOptions configures a priority stage created by PriorityStage.
+type StageOptions struct {
+	// MaxBuffered is the maximum number of elements buffered by the stage.
+	// Once reached, the stage stops reading its input until an element is
+	// sent, which applies backpressure to the producers.
+	// Zero means no limit.
+	MaxBuffered int
+	// FlushOnClose makes the stage send all the buffered elements after
+	// its input is closed. Otherwise the buffered elements are dropped.
+	FlushOnClose bool
+}
+
+// PriorityStage reads elements from in, buffers them in a ConcurrentMaxPQ
+// and sends them on the returned channel highest priority first, which
+// makes it possible to reorder elements between two stages of a pipeline.
+//
+// The returned channel is closed once in is closed (and the buffered
+// elements are flushed, if set by opts) or ctx is done.
+func PriorityStage[T comparable](ctx context.Context, in <-chan T, compareFn Comparator[T], opts StageOptions) <-chan T {
+	out := make(chan T)
+	pq := NewConcurrentMaxPQ[T](uint(opts.MaxBuffered), compareFn)
+	go func() {
+		defer close(out)
+		for in != nil {
+			recv := in
+			if opts.MaxBuffered > 0 && pq.Size() >= opts.MaxBuffered {
+				recv = nil
+			}
+			var send chan<- T
+			var next T
+			if pq.Size() > 0 {
+				send, next = out, pq.PeekMax()
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-recv:
+				if !ok {
+					in = nil
+					continue
+				}
+				pq.Insert(item)
+			case send <- next:
+				pq.DelMax()
+			}
+		}
+		if !opts.FlushOnClose {
+			return
+		}
+		for item := range pq.Drain() {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- item:
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,66 @@
+package collections
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityStage_FlushOnClose(t *testing.T) {
+	in := make(chan int)
+	out := PriorityStage(context.Background(), in, nil, StageOptions{FlushOnClose: true})
+	for _, v := range []int{3, 9, 1, 7, 5} {
+		in <- v
+	}
+	close(in)
+
+	got := make([]int, 0, 5)
+	for item := range out {
+		got = append(got, item)
+	}
+	require.EqualValues(t, []int{9, 7, 5, 3, 1}, got)
+}
+
+func TestPriorityStage_Close(t *testing.T) {
+	in := make(chan int)
+	out := PriorityStage(context.Background(), in, nil, StageOptions{})
+	in <- 1
+	in <- 2
+	close(in)
+
+	for range out {
+	}
+}
+
+func TestPriorityStage_MaxBuffered(t *testing.T) {
+	in := make(chan temp)
+	out := PriorityStage(context.Background(), in, Compare, StageOptions{MaxBuffered: 2, FlushOnClose: true})
+	in <- temp{val: 1}
+	in <- temp{val: 2}
+
+	select {
+	case in <- temp{val: 3}:
+		t.Fatal("stage read beyond MaxBuffered")
+	default:
+	}
+
+	require.EqualValues(t, temp{val: 2}, <-out)
+	in <- temp{val: 3}
+	close(in)
+	require.EqualValues(t, temp{val: 3}, <-out)
+	require.EqualValues(t, temp{val: 1}, <-out)
+	_, ok := <-out
+	require.False(t, ok)
+}
+
+func TestPriorityStage_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := PriorityStage(ctx, in, nil, StageOptions{FlushOnClose: true})
+	in <- 1
+	cancel()
+
+	for range out {
+	}
+}
@@ -0,0 +1,357 @@
+package collections
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	ErrEmptyQueue = errors.New("empty queue")
+	ErrCorruptLog = errors.New("corrupt log")
+	// ErrCompactFailed is wrapped by the errors of operations which
+	// were logged and applied, but whose automatic compaction failed.
+	// Such operations must not be retried.
+	ErrCompactFailed = errors.New("log compaction failed")
+)
+
+// Codec encodes elements to bytes and decodes them back.
+type Codec[T any] interface {
+	Encode(item T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// SyncPolicy decides when a DurableMaxPQ flushes its log to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways syncs the log after every operation, so no acknowledged
+	// operation is lost on a crash.
+	SyncAlways SyncPolicy = iota
+	// SyncNever leaves flushing to the operating system. Operations are
+	// not lost if the process crashes, but may be if the machine does.
+	// Sync can be called to flush the log explicitly.
+	SyncNever
+)
+
+// LogOptions configures the log of a DurableMaxPQ.
+type LogOptions struct {
+	Sync SyncPolicy
+	// CompactAfter is the number of operations appended to the log after
+	// which it is compacted automatically. Zero disables automatic compaction.
+	CompactAfter int
+}
+
+const (
+	opInsert byte = iota + 1
+	opDelMax
+)
+
+// record header: op (1 byte), payload length (4 bytes) and a crc32 of
+// both (4 bytes), followed by the payload and a crc32 of header and
+// payload. The header checksum tells a record cut short at the end of
+// the log from a record whose length is corrupt.
+const (
+	recordHeaderSize = 9
+	recordCRCSize    = 4
+)
+
+// DurableMaxPQ is a MaxPQ whose operations are recorded in a write-ahead
+// log, so that its contents survive a restart. Every Insert and DelMax is
+// appended to the log before being applied, and Recover rebuilds the heap
+// by replaying the log.
+//
+// The log is compacted by rewriting it as a snapshot of the current
+// elements, either explicitly with Compact or automatically as set by
+// LogOptions.CompactAfter.
+//
+// All operations of this type are thread-safe.
+type DurableMaxPQ[T comparable] struct {
+	mu      sync.Mutex
+	pq      *MaxPQ[T]
+	codec   Codec[T]
+	opts    LogOptions
+	path    string
+	f       *os.File
+	off     int64 // end of the last complete record
+	appends int
+}
+
+// Insert logs and inserts the given element in the collection.
+// If the returned error wraps ErrCompactFailed, the element was
+// inserted and only the automatic compaction of the log failed.
+func (d *DurableMaxPQ[T]) Insert(item T) error {
+	data, err := d.codec.Encode(item)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.append(opInsert, data); err != nil {
+		return err
+	}
+	d.pq.Insert(item)
+	return d.maybeCompact()
+}
+
+// DelMax logs the deletion of the current max element, deletes it from
+// the collection and returns it. ErrEmptyQueue is returned if the
+// collection is empty. If the returned error wraps ErrCompactFailed,
+// the element was deleted and is returned, and only the automatic
+// compaction of the log failed.
+func (d *DurableMaxPQ[T]) DelMax() (T, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var zero T
+	if d.pq.Size() == 0 {
+		return zero, ErrEmptyQueue
+	}
+	if err := d.append(opDelMax, nil); err != nil {
+		return zero, err
+	}
+	return d.pq.DelMax(), d.maybeCompact()
+}
+
+// PeekMax returns the current max/head element.
+func (d *DurableMaxPQ[T]) PeekMax() T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pq.PeekMax()
+}
+
+// Size returns the number of elements in the collection.
+func (d *DurableMaxPQ[T]) Size() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pq.Size()
+}
+
+// Compact atomically replaces the log with a snapshot of the current
+// elements, so that it no longer grows with the number of operations.
+func (d *DurableMaxPQ[T]) Compact() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.compact()
+}
+
+// Sync flushes the log to stable storage.
+func (d *DurableMaxPQ[T]) Sync() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.f.Sync()
+}
+
+// Close syncs and closes the log. The collection must not be
+// used after it is closed.
+func (d *DurableMaxPQ[T]) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.f.Sync(); err != nil {
+		d.f.Close()
+		return err
+	}
+	return d.f.Close()
+}
+
+// append writes a record at the end of the log. If it fails, the log
+// is truncated back to its previous end, so that a partial record is
+// not followed by the next ones.
+func (d *DurableMaxPQ[T]) append(op byte, data []byte) error {
+	rec := encodeRecord(op, data)
+	_, err := d.f.Write(rec)
+	if err == nil && d.opts.Sync == SyncAlways {
+		err = d.f.Sync()
+	}
+	if err != nil {
+		if terr := d.f.Truncate(d.off); terr != nil {
+			return errors.Join(err, terr)
+		}
+		if _, serr := d.f.Seek(d.off, io.SeekStart); serr != nil {
+			return errors.Join(err, serr)
+		}
+		return err
+	}
+	d.off += int64(len(rec))
+	d.appends++
+	return nil
+}
+
+func (d *DurableMaxPQ[T]) maybeCompact() error {
+	if d.opts.CompactAfter > 0 && d.appends >= d.opts.CompactAfter {
+		if err := d.compact(); err != nil {
+			return fmt.Errorf("%w: %w", ErrCompactFailed, err)
+		}
+	}
+	return nil
+}
+
+// compact writes the elements to a temporary file as insert records in
+// heap order, which replays to the exact same heap, and renames it over
+// the log.
+func (d *DurableMaxPQ[T]) compact() error {
+	tmp := d.path + ".compact"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	var off int64
+	for _, item := range d.pq.items {
+		data, err := d.codec.Encode(item)
+		if err == nil {
+			var n int
+			n, err = w.Write(encodeRecord(opInsert, data))
+			off += int64(n)
+		}
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, d.path); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	syncDir(filepath.Dir(d.path))
+	d.f.Close()
+	d.f = f
+	d.off = off
+	d.appends = 0
+	return nil
+}
+
+func encodeRecord(op byte, data []byte) []byte {
+	rec := make([]byte, recordHeaderSize+len(data)+recordCRCSize)
+	rec[0] = op
+	binary.LittleEndian.PutUint32(rec[1:5], uint32(len(data)))
+	binary.LittleEndian.PutUint32(rec[5:recordHeaderSize], crc32.ChecksumIEEE(rec[:5]))
+	copy(rec[recordHeaderSize:], data)
+	n := recordHeaderSize + len(data)
+	binary.LittleEndian.PutUint32(rec[n:], crc32.ChecksumIEEE(rec[:n]))
+	return rec
+}
+
+// syncDir syncs a directory so that a rename in it is durable.
+// Errors are ignored as not every platform supports it.
+func syncDir(dir string) {
+	if f, err := os.Open(dir); err == nil {
+		f.Sync()
+		f.Close()
+	}
+}
+
+// Recover opens the log at the given path, creating it if it does not
+// exist, and rebuilds the collection by replaying it.
+//
+// A record cut short at the end of the log, as left by a crash in the
+// middle of a write, is discarded and the log is truncated before it.
+// ErrCorruptLog is returned for any other invalid record, and the log
+// is left unchanged.
+func Recover[T comparable](path string, codec Codec[T], compareFn Comparator[T], opts LogOptions) (*DurableMaxPQ[T], error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	d := &DurableMaxPQ[T]{
+		pq:    NewMaxPQ(0, compareFn),
+		codec: codec,
+		opts:  opts,
+		path:  path,
+		f:     f,
+	}
+	valid, err := d.replay(f)
+	if err == nil {
+		err = f.Truncate(valid)
+	}
+	if err == nil {
+		_, err = f.Seek(valid, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	d.off = valid
+	return d, nil
+}
+
+// replay applies all the valid records of the log and returns the
+// offset of the end of the last one.
+func (d *DurableMaxPQ[T]) replay(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	r := bufio.NewReader(f)
+	var valid int64
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return valid, nil
+			}
+			return valid, err
+		}
+		if crc32.ChecksumIEEE(header[:5]) != binary.LittleEndian.Uint32(header[5:]) {
+			return valid, fmt.Errorf("%w: bad header checksum at offset %d", ErrCorruptLog, valid)
+		}
+		n := int64(binary.LittleEndian.Uint32(header[1:5]))
+		end := valid + recordHeaderSize + n + recordCRCSize
+		if end > info.Size() {
+			// torn write at the end of the log, the length is
+			// known to be valid thanks to the header checksum
+			return valid, nil
+		}
+		rest := make([]byte, n+recordCRCSize)
+		if _, err := io.ReadFull(r, rest); err != nil {
+			return valid, err
+		}
+		crc := crc32.Update(crc32.ChecksumIEEE(header), crc32.IEEETable, rest[:n])
+		if crc != binary.LittleEndian.Uint32(rest[n:]) {
+			if end == info.Size() {
+				return valid, nil
+			}
+			return valid, fmt.Errorf("%w: bad checksum at offset %d", ErrCorruptLog, valid)
+		}
+		if err := d.apply(header[0], rest[:n]); err != nil {
+			return valid, fmt.Errorf("%w: record at offset %d: %v", ErrCorruptLog, valid, err)
+		}
+		valid = end
+	}
+}
+
+func (d *DurableMaxPQ[T]) apply(op byte, data []byte) error {
+	switch op {
+	case opInsert:
+		item, err := d.codec.Decode(data)
+		if err != nil {
+			return err
+		}
+		d.pq.Insert(item)
+	case opDelMax:
+		if d.pq.Size() == 0 {
+			return ErrEmptyQueue
+		}
+		d.pq.DelMax()
+	default:
+		return fmt.Errorf("unknown operation %d", op)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package collections
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type intCodec struct{}
+
+func (intCodec) Encode(item int) ([]byte, error) {
+	return binary.AppendVarint(nil, int64(item)), nil
+}
+
+func (intCodec) Decode(data []byte) (int, error) {
+	v, n := binary.Varint(data)
+	if n <= 0 {
+		return 0, errors.New("bad varint")
+	}
+	return int(v), nil
+}
+
+func setupDurablePQ(t *testing.T, opts LogOptions) (*DurableMaxPQ[int], string) {
+	path := filepath.Join(t.TempDir(), "pq.log")
+	d, err := Recover[int](path, intCodec{}, nil, opts)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, d.Insert(i))
+	}
+	for i := 0; i < 3; i++ {
+		_, err := d.DelMax()
+		require.NoError(t, err)
+	}
+	return d, path
+}
+
+func TestDurableMaxPQ_Recover(t *testing.T) {
+	d, path := setupDurablePQ(t, LogOptions{})
+	require.NoError(t, d.Close())
+
+	d, err := Recover[int](path, intCodec{}, nil, LogOptions{})
+	require.NoError(t, err)
+	require.EqualValues(t, 7, d.Size())
+	require.EqualValues(t, 6, d.PeekMax())
+
+	require.NoError(t, d.Insert(42))
+	require.NoError(t, d.Close())
+
+	d, err = Recover[int](path, intCodec{}, nil, LogOptions{})
+	require.NoError(t, err)
+	defer d.Close()
+	for _, want := range []int{42, 6, 5, 4, 3, 2, 1, 0} {
+		got, err := d.DelMax()
+		require.NoError(t, err)
+		require.EqualValues(t, want, got)
+	}
+	_, err = d.DelMax()
+	require.ErrorIs(t, err, ErrEmptyQueue)
+}
+
+func TestDurableMaxPQ_TruncatedLog(t *testing.T) {
+	for _, cut := range []int64{1, 3, 5, 7} {
+		d, path := setupDurablePQ(t, LogOptions{Sync: SyncNever})
+		require.NoError(t, d.Insert(100))
+		require.NoError(t, d.Close())
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, os.Truncate(path, info.Size()-cut))
+
+		d, err = Recover[int](path, intCodec{}, nil, LogOptions{})
+		require.NoError(t, err)
+		require.EqualValues(t, 7, d.Size())
+		require.EqualValues(t, 6, d.PeekMax())
+
+		require.NoError(t, d.Insert(50))
+		require.NoError(t, d.Close())
+		d, err = Recover[int](path, intCodec{}, nil, LogOptions{})
+		require.NoError(t, err)
+		require.EqualValues(t, 8, d.Size())
+		require.EqualValues(t, 50, d.PeekMax())
+		require.NoError(t, d.Close())
+	}
+}
+
+func TestDurableMaxPQ_CorruptLog(t *testing.T) {
+	d, path := setupDurablePQ(t, LogOptions{})
+	require.NoError(t, d.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[recordHeaderSize] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	_, err = Recover[int](path, intCodec{}, nil, LogOptions{})
+	require.ErrorIs(t, err, ErrCorruptLog)
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.EqualValues(t, data, got)
+}
+
+func TestDurableMaxPQ_CorruptLength(t *testing.T) {
+	d, path := setupDurablePQ(t, LogOptions{})
+	require.NoError(t, d.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	// grow the length of the second record past the end of the log
+	rec := recordHeaderSize + 1 + recordCRCSize
+	binary.LittleEndian.PutUint32(data[rec+1:], 1<<20)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	_, err = Recover[int](path, intCodec{}, nil, LogOptions{})
+	require.ErrorIs(t, err, ErrCorruptLog)
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.EqualValues(t, data, got)
+}
+
+func TestDurableMaxPQ_CompactFailed(t *testing.T) {
+	d, path := setupDurablePQ(t, LogOptions{CompactAfter: 1})
+	defer d.Close()
+	// a directory in the way of the temporary file makes compaction fail
+	require.NoError(t, os.Mkdir(path+".compact", 0o755))
+
+	err := d.Insert(42)
+	require.ErrorIs(t, err, ErrCompactFailed)
+	require.EqualValues(t, 8, d.Size())
+	got, err := d.DelMax()
+	require.ErrorIs(t, err, ErrCompactFailed)
+	require.EqualValues(t, 42, got)
+	require.EqualValues(t, 7, d.Size())
+}
+
+func TestDurableMaxPQ_Compact(t *testing.T) {
+	d, path := setupDurablePQ(t, LogOptions{CompactAfter: 4})
+	for i := 0; i < 50; i++ {
+		require.NoError(t, d.Insert(i))
+		_, err := d.DelMax()
+		require.NoError(t, err)
+	}
+	require.NoError(t, d.Compact())
+	require.NoError(t, d.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.LessOrEqual(t, info.Size(), int64(7*(recordHeaderSize+2+recordCRCSize)))
+
+	want := d.pq.Sorted()
+	d, err = Recover[int](path, intCodec{}, nil, LogOptions{})
+	require.NoError(t, err)
+	defer d.Close()
+	require.EqualValues(t, want, d.pq.Sorted())
+}
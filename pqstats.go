@@ -0,0 +1,178 @@
+package collections
+
+import "time"
+
+// PQObserver receives events from a MaxPQ with statistics enabled,
+// which allows bridging them to a metrics library or expvar.
+//
+// Methods are called synchronously while the queue is being modified
+// (and, for ConcurrentMaxPQ, while its lock is held), so they must
+// return quickly and must not call back into the queue.
+type PQObserver interface {
+	// Inserted is called after an element is inserted.
+	Inserted(size int)
+	// Popped is called when the max element is deleted, with the time
+	// the element spent in the queue.
+	Popped(size int, inQueue time.Duration)
+	// Removed is called after n elements are deleted by Remove or
+	// RemoveFunc.
+	Removed(size, n int)
+	// LockWaited is called with the time a ConcurrentMaxPQ operation
+	// waited to acquire the write lock.
+	LockWaited(wait time.Duration)
+}
+
+// PQStats is a snapshot of the statistics of a MaxPQ.
+type PQStats struct {
+	// Len is the number of elements in the queue.
+	Len int
+	// HighWater is the largest number of elements held by the queue.
+	HighWater int
+	// Inserts is the number of inserted elements.
+	Inserts uint64
+	// Pops is the number of elements deleted as the max element.
+	Pops uint64
+	// Removes is the number of elements deleted by Remove and RemoveFunc.
+	Removes uint64
+	// InQueue is the total time spent in the queue by popped elements.
+	InQueue time.Duration
+	// LockWait is the total time spent waiting for the write lock
+	// of a ConcurrentMaxPQ.
+	LockWait time.Duration
+	// Since is the time statistics were enabled.
+	Since time.Time
+	// At is the time the snapshot was taken.
+	At time.Time
+}
+
+// InsertRate returns the average number of inserts per second.
+func (s PQStats) InsertRate() float64 {
+	return rate(s.Inserts, s.At.Sub(s.Since))
+}
+
+// PopRate returns the average number of pops per second.
+func (s PQStats) PopRate() float64 {
+	return rate(s.Pops, s.At.Sub(s.Since))
+}
+
+// MeanInQueue returns the average time spent in the queue by popped elements.
+func (s PQStats) MeanInQueue() time.Duration {
+	if s.Pops == 0 {
+		return 0
+	}
+	return s.InQueue / time.Duration(s.Pops)
+}
+
+func rate(n uint64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+type pqStats struct {
+	observer  PQObserver
+	since     time.Time
+	highWater int
+	inserts   uint64
+	pops      uint64
+	removes   uint64
+	inQueue   time.Duration
+	lockWait  time.Duration
+}
+
+func (s *pqStats) inserted(size int) {
+	s.inserts++
+	if size > s.highWater {
+		s.highWater = size
+	}
+	if s.observer != nil {
+		s.observer.Inserted(size)
+	}
+}
+
+func (s *pqStats) popped(size int, inQueue time.Duration) {
+	s.pops++
+	s.inQueue += inQueue
+	if s.observer != nil {
+		s.observer.Popped(size, inQueue)
+	}
+}
+
+func (s *pqStats) removed(size, n int) {
+	s.removes += uint64(n)
+	if s.observer != nil {
+		s.observer.Removed(size, n)
+	}
+}
+
+func (s *pqStats) lockWaited(wait time.Duration) {
+	s.lockWait += wait
+	if s.observer != nil {
+		s.observer.LockWaited(wait)
+	}
+}
+
+// EnableStats starts collecting statistics, available from Stats, and
+// reports events to the given observer, which may be nil. Elements
+// already in the collection are considered inserted at the time of
+// the call. Calling EnableStats again resets the statistics.
+func (pq *MaxPQ[T]) EnableStats(observer PQObserver) {
+	now := time.Now()
+	pq.stats = &pqStats{
+		observer:  observer,
+		since:     now,
+		highWater: len(pq.items),
+	}
+	pq.enq = make([]time.Time, len(pq.items), cap(pq.items))
+	for i := range pq.enq {
+		pq.enq[i] = now
+	}
+}
+
+// Stats returns a snapshot of the statistics of the collection.
+// Only Len is set if statistics are not enabled.
+func (pq *MaxPQ[T]) Stats() PQStats {
+	st := PQStats{
+		Len: len(pq.items),
+		At:  time.Now(),
+	}
+	if s := pq.stats; s != nil {
+		st.HighWater = s.highWater
+		st.Inserts = s.inserts
+		st.Pops = s.pops
+		st.Removes = s.removes
+		st.InQueue = s.inQueue
+		st.LockWait = s.lockWait
+		st.Since = s.since
+	}
+	return st
+}
+
+// EnableStats starts collecting statistics, including the time spent
+// waiting for the write lock. See MaxPQ.EnableStats.
+func (pq *ConcurrentMaxPQ[T]) EnableStats(observer PQObserver) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	pq.MaxPQ.EnableStats(observer)
+	pq.timed.Store(true)
+}
+
+// Stats returns a snapshot of the statistics of the collection.
+func (pq *ConcurrentMaxPQ[T]) Stats() PQStats {
+	pq.mu.RLock()
+	defer pq.mu.RUnlock()
+	return pq.MaxPQ.Stats()
+}
+
+// lock acquires the write lock, recording the time spent waiting
+// for it if statistics are enabled.
+func (pq *ConcurrentMaxPQ[T]) lock() {
+	if !pq.timed.Load() {
+		pq.mu.Lock()
+		return
+	}
+	start := time.Now()
+	pq.mu.Lock()
+	pq.MaxPQ.stats.lockWaited(time.Since(start))
+}
@@ -0,0 +1,139 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingObserver struct {
+	mu                       sync.Mutex
+	inserted, popped, waited int
+	removed                  int
+	lastSize                 int
+	inQueue                  time.Duration
+}
+
+func (o *countingObserver) Inserted(size int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.inserted++
+	o.lastSize = size
+}
+
+func (o *countingObserver) Popped(size int, inQueue time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.popped++
+	o.lastSize = size
+	o.inQueue += inQueue
+}
+
+func (o *countingObserver) Removed(size, n int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.removed += n
+	o.lastSize = size
+}
+
+func (o *countingObserver) LockWaited(time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.waited++
+}
+
+func TestMaxPQ_Stats(t *testing.T) {
+	pq := setupPQWithInts(t)
+	require.EqualValues(t, 10, pq.Stats().Len)
+	require.EqualValues(t, 0, pq.Stats().Inserts)
+
+	o := &countingObserver{}
+	pq.EnableStats(o)
+	pq.PushAll(10, 11, 12)
+	pq.DelMax()
+	pq.DelMax()
+	pq.Remove(3)
+	pq.RemoveFunc(func(item int) bool { return item < 2 })
+	require.NoError(t, pq.Validate())
+
+	st := pq.Stats()
+	require.EqualValues(t, 8, st.Len)
+	require.EqualValues(t, 13, st.HighWater)
+	require.EqualValues(t, 3, st.Inserts)
+	require.EqualValues(t, 2, st.Pops)
+	require.EqualValues(t, 3, st.Removes)
+	require.False(t, st.Since.After(st.At))
+	require.GreaterOrEqual(t, st.InsertRate(), 0.0)
+	require.EqualValues(t, st.InQueue/2, st.MeanInQueue())
+
+	require.EqualValues(t, 3, o.inserted)
+	require.EqualValues(t, 2, o.popped)
+	require.EqualValues(t, 3, o.removed)
+	require.EqualValues(t, st.Len, o.lastSize)
+	require.EqualValues(t, st.InQueue, o.inQueue)
+}
+
+func TestMaxPQ_StatsNotCloned(t *testing.T) {
+	pq := NewMaxPQ[int](0, nil)
+	pq.EnableStats(nil)
+	pq.PushAll(1, 2, 3)
+
+	require.EqualValues(t, []int{3, 2, 1}, pq.Sorted())
+	require.EqualValues(t, 0, pq.Stats().Pops)
+	require.EqualValues(t, 0, pq.Clone().Stats().Inserts)
+}
+
+func TestConcurrentMaxPQ_Stats(t *testing.T) {
+	pq := NewConcurrentMaxPQ[int](0, nil)
+	o := &countingObserver{}
+	pq.EnableStats(o)
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for i := 0; i < 4; i++ {
+		go func(i int) {
+			for j := 0; j < 50; j++ {
+				pq.Insert(i*50 + j)
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+	for range pq.Drain() {
+	}
+
+	st := pq.Stats()
+	require.EqualValues(t, 0, st.Len)
+	require.EqualValues(t, 200, st.HighWater)
+	require.EqualValues(t, 200, st.Inserts)
+	require.EqualValues(t, 200, st.Pops)
+	// every insert, every pop and the final check of the empty queue
+	require.EqualValues(t, 401, o.waited)
+}
+
+func TestConcurrentMaxPQ_DrainStats(t *testing.T) {
+	pq := NewConcurrentMaxPQ[int](0, nil)
+	o := &countingObserver{}
+	pq.EnableStats(o)
+	pq.PushAll(1, 2, 3, 4)
+
+	for range pq.Drain() {
+		break
+	}
+
+	st := pq.Stats()
+	require.EqualValues(t, 3, st.Len)
+	require.EqualValues(t, 4, st.HighWater)
+	require.EqualValues(t, 4, st.Inserts)
+	require.EqualValues(t, 1, st.Pops)
+	require.EqualValues(t, 1, o.popped)
+	require.EqualValues(t, 3, o.lastSize)
+
+	// elements left after stopping keep their insertion time
+	time.Sleep(10 * time.Millisecond)
+	pq.DelMax()
+	st = pq.Stats()
+	require.EqualValues(t, 2, st.Pops)
+	require.GreaterOrEqual(t, st.InQueue, 10*time.Millisecond)
+}
@@ -1,6 +1,9 @@
 package collections
 
-import "math"
+import (
+	"iter"
+	"math"
+)
 
 type Comparable interface {
 	int64 | int | float64 | uint | uint64 | string
@@ -57,7 +60,7 @@ func (t *RBTree[K, V]) Get(key K) (V, bool) {
 }
 
 func (t *RBTree[K, V]) IsEmpty() bool {
-	return t.root.size == 0
+	return size(t.root) == 0
 }
 
 func (t *RBTree[K, V]) Min() *Node[K, V] {
@@ -79,6 +82,9 @@ func (t *RBTree[K, V]) Size() int {
 }
 
 func (t *RBTree[K, V]) DeleteMin() {
+	if t.IsEmpty() {
+		return
+	}
 	if !isRed(t.root.left) && !isRed(t.root.right) {
 		t.root.color = Red
 	}
@@ -89,6 +95,9 @@ func (t *RBTree[K, V]) DeleteMin() {
 }
 
 func (t *RBTree[K, V]) DeleteMax() {
+	if t.IsEmpty() {
+		return
+	}
 	if !isRed(t.root.left) && !isRed(t.root.right) {
 		t.root.color = Red
 	}
@@ -131,6 +140,61 @@ func (t *RBTree[K, V]) Rank(key K) int {
 	return rank(t.root, key, t.compareFn)
 }
 
+// Floor returns the node with the largest key less than or equal to
+// the given key, or nil if there is no such key.
+func (t *RBTree[K, V]) Floor(key K) *Node[K, V] {
+	var floor *Node[K, V]
+	for node := t.root; node != nil; {
+		cmp := compare(key, node.key, t.compareFn)
+		if cmp == 0 {
+			return node
+		}
+		if cmp < 0 {
+			node = node.left
+		} else {
+			floor = node
+			node = node.right
+		}
+	}
+	return floor
+}
+
+// Ceiling returns the node with the smallest key greater than or equal
+// to the given key, or nil if there is no such key.
+func (t *RBTree[K, V]) Ceiling(key K) *Node[K, V] {
+	var ceiling *Node[K, V]
+	for node := t.root; node != nil; {
+		cmp := compare(key, node.key, t.compareFn)
+		if cmp == 0 {
+			return node
+		}
+		if cmp > 0 {
+			node = node.right
+		} else {
+			ceiling = node
+			node = node.left
+		}
+	}
+	return ceiling
+}
+
+// All returns an iterator over the keys and values of the tree
+// in key order. The tree must not be modified during iteration.
+func (t *RBTree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		inOrderFunc(t.root, yield)
+	}
+}
+
+// Range returns an iterator over the keys and values of the tree with
+// keys between lo and hi, both inclusive, in key order. The tree must
+// not be modified during iteration.
+func (t *RBTree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		rangeFunc(t.root, lo, hi, t.compareFn, yield)
+	}
+}
+
 func rank[K Comparable, V any](node *Node[K, V], key K, compareFn CompareFn[K]) int {
 	if node == nil {
 		return 0
@@ -155,6 +219,90 @@ func inOrder[K Comparable, V any](node *Node[K, V], keys []K) []K {
 	return keys
 }
 
+// inOrderFunc calls yield for every node in key order
+// until it returns false, in which case it returns false.
+func inOrderFunc[K Comparable, V any](node *Node[K, V], yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+	return inOrderFunc(node.left, yield) &&
+		yield(node.key, node.val) &&
+		inOrderFunc(node.right, yield)
+}
+
+// rangeFunc is inOrderFunc limited to the keys between lo and hi,
+// skipping the subtrees out of the range.
+func rangeFunc[K Comparable, V any](node *Node[K, V], lo, hi K, compareFn CompareFn[K], yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+	cmpLo := compare(lo, node.key, compareFn)
+	cmpHi := compare(hi, node.key, compareFn)
+	if cmpLo < 0 && !rangeFunc(node.left, lo, hi, compareFn, yield) {
+		return false
+	}
+	if cmpLo <= 0 && cmpHi >= 0 && !yield(node.key, node.val) {
+		return false
+	}
+	if cmpHi > 0 {
+		return rangeFunc(node.right, lo, hi, compareFn, yield)
+	}
+	return true
+}
+
+// fromSorted builds a tree holding the given strictly increasing keys
+// with zero values in O(n), as a 2-3 tree of the minimum black height
+// whose 3-nodes are a black node with a red left child.
+func fromSorted[K Comparable, V any](keys []K) *Node[K, V] {
+	h := 0
+	for n := len(keys) + 1; n > 1; n >>= 1 {
+		h++
+	}
+	return buildBlack[K, V](keys, h)
+}
+
+// buildBlack builds a tree of the given black height, which must be
+// able to hold len(keys) keys, that is between 2^h-1 and 3^h-1 of them.
+func buildBlack[K Comparable, V any](keys []K, h int) *Node[K, V] {
+	n := len(keys)
+	if h == 0 {
+		return nil
+	}
+	maxChild := 1
+	for i := 1; i < h; i++ {
+		maxChild *= 3
+	}
+	maxChild--
+	if n <= 2*maxChild+1 {
+		// 2-node
+		l := (n - 1) / 2
+		return &Node[K, V]{
+			key:   keys[l],
+			left:  buildBlack[K, V](keys[:l], h-1),
+			right: buildBlack[K, V](keys[l+1:], h-1),
+			size:  n,
+			color: Black,
+		}
+	}
+	// 3-node, splitting the remaining keys evenly between the children
+	c := n - 2
+	l, m := (c+2)/3, (c+1)/3
+	red := &Node[K, V]{
+		key:   keys[l],
+		left:  buildBlack[K, V](keys[:l], h-1),
+		right: buildBlack[K, V](keys[l+1:l+1+m], h-1),
+		size:  l + m + 1,
+		color: Red,
+	}
+	return &Node[K, V]{
+		key:   keys[l+1+m],
+		left:  red,
+		right: buildBlack[K, V](keys[l+2+m:], h-1),
+		size:  n,
+		color: Black,
+	}
+}
+
 func isRed[K Comparable, V any](node *Node[K, V]) bool {
 	if node == nil {
 		return false
@@ -318,9 +466,9 @@ func rotateRight[K Comparable, V any](node *Node[K, V]) *Node[K, V] {
 
 func flipColors[K Comparable, V any](node *Node[K, V]) {
 	// bitwise xor to flip colors (0 and 1)
-	node.color ^= node.color
-	node.left.color ^= node.left.color
-	node.right.color ^= node.right.color
+	node.color ^= 1
+	node.left.color ^= 1
+	node.right.color ^= 1
 }
 
 func balance[K Comparable, V any](node *Node[K, V]) *Node[K, V] {
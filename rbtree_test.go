@@ -89,6 +89,133 @@ func TestRBTree_Rank(t *testing.T) {
 	require.EqualValues(t, 100, got)
 }
 
+func TestRBTree_Floor(t *testing.T) {
+	rbt := New[int, string]()
+	for i := 0; i < 100; i += 10 {
+		rbt.Put(i, fmt.Sprintf("val_%d", i))
+	}
+
+	require.EqualValues(t, 50, rbt.Floor(50).Key())
+	require.EqualValues(t, 50, rbt.Floor(55).Key())
+	require.EqualValues(t, 90, rbt.Floor(1000).Key())
+	require.Nil(t, rbt.Floor(-1))
+}
+
+func TestRBTree_Ceiling(t *testing.T) {
+	rbt := New[int, string]()
+	for i := 0; i < 100; i += 10 {
+		rbt.Put(i, fmt.Sprintf("val_%d", i))
+	}
+
+	require.EqualValues(t, 50, rbt.Ceiling(50).Key())
+	require.EqualValues(t, 60, rbt.Ceiling(55).Key())
+	require.EqualValues(t, 0, rbt.Ceiling(-1).Key())
+	require.Nil(t, rbt.Ceiling(91))
+}
+
+func TestRBTree_Range(t *testing.T) {
+	setup()
+	var keys []int
+	for k, v := range rbt.Range(100, 110) {
+		require.EqualValues(t, fmt.Sprintf("val_%d", k), v)
+		keys = append(keys, k)
+	}
+	require.EqualValues(t, []int{100, 101, 102, 103, 104, 105, 106, 107, 108, 109, 110}, keys)
+
+	keys = keys[:0]
+	for k := range rbt.Range(995, 2000) {
+		keys = append(keys, k)
+	}
+	require.EqualValues(t, []int{995, 996, 997, 998, 999}, keys)
+
+	for range rbt.Range(10, 5) {
+		t.Fatal("Range() with lo > hi yielded a key")
+	}
+}
+
+func TestRBTree_All(t *testing.T) {
+	setup()
+	want := 0
+	for k := range rbt.All() {
+		require.EqualValues(t, want, k)
+		if want == 99 {
+			break
+		}
+		want++
+	}
+	require.EqualValues(t, 99, want)
+}
+
+func TestRBTree_Empty(t *testing.T) {
+	rbt := New[int, string]()
+	require.True(t, rbt.IsEmpty())
+	rbt.DeleteMin()
+	rbt.DeleteMax()
+	rbt.Delete(1)
+
+	rbt.Put(1, "one")
+	rbt.Delete(1)
+	require.True(t, rbt.IsEmpty())
+	require.EqualValues(t, 0, rbt.Size())
+}
+
+func TestRBTree_DeleteBalanced(t *testing.T) {
+	setup()
+	for i, k := range rand.Perm(1000) {
+		rbt.Delete(k)
+		require.False(t, rbt.Has(k))
+		require.EqualValues(t, 999-i, rbt.Size())
+		if i%50 == 0 {
+			requireLLRB(t, rbt.root, nil)
+		}
+	}
+	require.True(t, rbt.IsEmpty())
+}
+
+func TestFromSorted(t *testing.T) {
+	for n := 0; n < 300; n++ {
+		keys := make([]int, n)
+		for i := range keys {
+			keys[i] = i * 2
+		}
+		rbt := New[int, struct{}]()
+		rbt.root = fromSorted[int, struct{}](keys)
+
+		requireLLRB(t, rbt.root, nil)
+		require.EqualValues(t, n, rbt.Size())
+		require.EqualValues(t, keys, rbt.Keys())
+
+		rbt.Put(-1, struct{}{})
+		rbt.Delete(0)
+		requireLLRB(t, rbt.root, nil)
+	}
+}
+
+// requireLLRB checks that the tree rooted at node is a valid left-leaning
+// red-black tree with correct sizes, and returns its black height.
+func requireLLRB[K Comparable, V any](t *testing.T, node *Node[K, V], compareFn CompareFn[K]) int {
+	t.Helper()
+	if node == nil {
+		return 0
+	}
+	require.False(t, isRed(node.right), "red right link at %v", node.key)
+	require.False(t, isRed(node) && isRed(node.left), "two red links in a row at %v", node.key)
+	require.EqualValues(t, size(node.left)+size(node.right)+1, node.size, "bad size at %v", node.key)
+	if node.left != nil {
+		require.Negative(t, compare(node.left.key, node.key, compareFn))
+	}
+	if node.right != nil {
+		require.Positive(t, compare(node.right.key, node.key, compareFn))
+	}
+	lh := requireLLRB(t, node.left, compareFn)
+	rh := requireLLRB(t, node.right, compareFn)
+	require.EqualValues(t, lh, rh, "unbalanced at %v", node.key)
+	if !isRed(node) {
+		lh++
+	}
+	return lh
+}
+
 func setup() {
 	arr := rand.Perm(1000)
 	rbt = New[int, string]()
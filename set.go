@@ -1,78 +1,204 @@
 package collections
 
-import "math"
+import (
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
 
 // Set provides a collection with no duplicates.
+//
+// Union, Intersection, Difference and SymmetricDifference return new
+// sets and never modify their operands, while UnionWith, IntersectWith
+// and DifferenceWith modify the current set in place. A nil *Set is
+// treated as an empty set by all the methods that do not modify it.
+//
+// Elements are stored in a map for O(1) Add, Contains and Remove, and
+// in a slice which keeps them in insertion order until an element is
+// removed: Remove moves the last element in the place of the removed
+// one, so the order of the elements is unspecified after a removal.
 type Set[T comparable] struct {
-	elems []T
+	index  map[T]int // position of every element in elems
+	elems  []T
+	strict bool // reject duplicates when decoding
 }
 
 // Add adds the given element to the set.
 // If the element already exists, it is a no-op.
 func (s *Set[T]) Add(e T) {
-	if !s.Contains(e) {
-		s.elems = append(s.elems, e)
+	if s.Contains(e) {
+		return
+	}
+	if s.index == nil {
+		s.index = make(map[T]int)
 	}
+	s.index[e] = len(s.elems)
+	s.elems = append(s.elems, e)
 }
 
-// Items returns the elements of the set as a slice.
-func (s *Set[T]) Items() []T {
-	return s.elems
+// Remove removes the given element from the set.
+// It returns true if the element was present.
+// The last element of the set takes the place of the removed one.
+func (s *Set[T]) Remove(e T) bool {
+	i, ok := s.index[e]
+	if !ok {
+		return false
+	}
+	last := len(s.elems) - 1
+	if i != last {
+		s.elems[i] = s.elems[last]
+		s.index[s.elems[i]] = i
+	}
+	var zero T
+	s.elems[last] = zero
+	s.elems = s.elems[:last]
+	delete(s.index, e)
+	return true
 }
 
-// Union adds all elements from s2 to the current set
-// which are not present in the current set.
-func (s *Set[T]) Union(s2 *Set[T]) {
-	if s2 == nil {
-		return
+// RemoveAll removes all the given elements from the set and
+// returns the number of elements that were present.
+func (s *Set[T]) RemoveAll(items ...T) int {
+	removed := 0
+	for _, e := range items {
+		if s.Remove(e) {
+			removed++
+		}
 	}
-	o1, o2 := bySize(s, s2)
+	return removed
+}
 
-	for _, e := range o1.Items() {
-		o2.Add(e)
+// Pop removes and returns an element of the set, the last one in the
+// order of Items. It returns the zero value and false if the set is empty.
+func (s *Set[T]) Pop() (T, bool) {
+	if len(s.elems) == 0 {
+		var zero T
+		return zero, false
+	}
+	e := s.elems[len(s.elems)-1]
+	s.Remove(e)
+	return e, true
+}
+
+// Clear removes all the elements from the set.
+func (s *Set[T]) Clear() {
+	clear(s.index)
+	clear(s.elems)
+	s.elems = s.elems[:0]
+}
+
+// Clone returns a copy of the set which can be modified
+// independently of the original.
+func (s *Set[T]) Clone() *Set[T] {
+	if s == nil {
+		return NewSet[T](0)
+	}
+	return &Set[T]{
+		index:  maps.Clone(s.index),
+		elems:  slices.Clone(s.elems),
+		strict: s.strict,
+	}
+}
+
+// Items returns a copy of the elements of the set as a slice.
+func (s *Set[T]) Items() []T {
+	if s == nil {
+		return nil
 	}
+	return slices.Clone(s.elems)
+}
+
+// Union returns a new set with the elements of the current set
+// followed by the elements of s2 which are not in the current set.
+func (s *Set[T]) Union(s2 *Set[T]) *Set[T] {
+	u := s.Clone()
+	u.UnionWith(s2)
+	return u
 }
 
 // Intersection returns a new set with elements that are present in
 // both the sets.
 func (s *Set[T]) Intersection(s2 *Set[T]) *Set[T] {
-	if s2 == nil {
-		return nil
+	common := NewSet[T](0)
+	if s2 == nil || s == nil {
+		return common
 	}
 	o1, o2 := bySize(s, s2)
 
-	common := make([]T, 0)
-	for _, e := range o1.Items() {
+	for _, e := range o1.elems {
 		if o2.Contains(e) {
-			common = append(common, e)
+			common.Add(e)
 		}
 	}
-	return &Set[T]{elems: common}
+	return common
 }
 
 // Difference returns a new set with elements that are in the current set but not in s2.
 func (s *Set[T]) Difference(s2 *Set[T]) *Set[T] {
-	if s2 == nil || s2.Size() == 0 {
-		var elems []T
-		copy(elems, s.elems)
-		return &Set[T]{elems: elems}
+	diff := NewSet[T](0)
+	if s == nil {
+		return diff
 	}
 
-	diff := make([]T, 0)
-
 	for _, e := range s.elems {
 		if s2.Contains(e) {
 			continue
 		}
-		diff = append(diff, e)
+		diff.Add(e)
+	}
+	return diff
+}
+
+// SymmetricDifference returns a new set with the elements that are in
+// either the current set or s2, but not in both.
+func (s *Set[T]) SymmetricDifference(s2 *Set[T]) *Set[T] {
+	diff := s.Difference(s2)
+	if s2 == nil {
+		return diff
+	}
+	for _, e := range s2.elems {
+		if !s.Contains(e) {
+			diff.Add(e)
+		}
+	}
+	return diff
+}
+
+// UnionWith adds all elements from s2 to the current set
+// which are not present in the current set.
+func (s *Set[T]) UnionWith(s2 *Set[T]) {
+	if s2 == nil {
+		return
+	}
+	for _, e := range s2.elems {
+		s.Add(e)
+	}
+}
+
+// IntersectWith removes the elements of the current set
+// which are not present in s2.
+func (s *Set[T]) IntersectWith(s2 *Set[T]) {
+	s.retain(s2.Contains)
+}
+
+// DifferenceWith removes the elements of the current set
+// which are present in s2.
+func (s *Set[T]) DifferenceWith(s2 *Set[T]) {
+	if s2.Size() == 0 {
+		return
 	}
-	return &Set[T]{elems: diff}
+	s.retain(func(e T) bool {
+		return !s2.Contains(e)
+	})
 }
 
 // IsDisjoint returns true if there are no common elements between
 // this set and the given set, else returns true.
 func (s *Set[T]) IsDisjoint(s2 *Set[T]) bool {
-	if s2 == nil || s2.Size() == 0 {
+	if s2.Size() == 0 {
 		return true
 	}
 
@@ -93,19 +219,17 @@ func (s *Set[T]) Contains(e T) bool {
 // Index returns the index of the given element in the set.
 // Returns -1 if the element is not present.
 func (s *Set[T]) Index(e T) int {
-	for i := range s.elems {
-		if e == s.elems[i] {
-			return i
-		}
+	if s == nil {
+		return -1
+	}
+	if i, ok := s.index[e]; ok {
+		return i
 	}
 	return -1
 }
 
 // IsSubsetOf returns true if this set is a subset of the given set.
 func (s *Set[T]) IsSubsetOf(s2 *Set[T]) bool {
-	if s2 == nil {
-		return false
-	}
 	if s.Size() == 0 {
 		return true
 	}
@@ -121,15 +245,132 @@ func (s *Set[T]) IsSubsetOf(s2 *Set[T]) bool {
 	return true
 }
 
+// IsProperSubsetOf returns true if this set is a subset of the given
+// set and the given set has at least one element not in this set.
+func (s *Set[T]) IsProperSubsetOf(s2 *Set[T]) bool {
+	return s.Size() < s2.Size() && s.IsSubsetOf(s2)
+}
+
+// IsSupersetOf returns true if the given set is a subset of this set.
+func (s *Set[T]) IsSupersetOf(s2 *Set[T]) bool {
+	return s2.IsSubsetOf(s)
+}
+
+// Equal returns true if both sets have the same elements,
+// regardless of the order in which they were added.
+func (s *Set[T]) Equal(s2 *Set[T]) bool {
+	return s.Size() == s2.Size() && s.IsSubsetOf(s2)
+}
+
 // Size returns the number of elements in the current set.
 func (s *Set[T]) Size() int {
+	if s == nil {
+		return 0
+	}
 	return len(s.elems)
 }
 
+// Values returns an iterator over the elements of the set in their
+// order in the set. The set must not be modified during iteration.
+func (s *Set[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if s == nil {
+			return
+		}
+		for _, e := range s.elems {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// ForEach calls fn for every element of the set, in their order in the
+// set, until fn returns false. The set must not be modified by fn.
+func (s *Set[T]) ForEach(fn func(e T) bool) {
+	for e := range s.Values() {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+// Filter returns a new set with the elements for which pred returns true.
+func (s *Set[T]) Filter(pred func(e T) bool) *Set[T] {
+	out := NewSet[T](0)
+	for e := range s.Values() {
+		if pred(e) {
+			out.Add(e)
+		}
+	}
+	return out
+}
+
+// Partition returns a new set with the elements for which pred returns
+// true and another one with the elements for which it returns false.
+func (s *Set[T]) Partition(pred func(e T) bool) (in, out *Set[T]) {
+	in, out = NewSet[T](0), NewSet[T](0)
+	for e := range s.Values() {
+		if pred(e) {
+			in.Add(e)
+		} else {
+			out.Add(e)
+		}
+	}
+	return in, out
+}
+
+// Any returns true if pred returns true for at least one element.
+func (s *Set[T]) Any(pred func(e T) bool) bool {
+	for e := range s.Values() {
+		if pred(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns true if pred returns true for every element,
+// which is always the case for an empty set.
+func (s *Set[T]) All(pred func(e T) bool) bool {
+	return !s.Any(func(e T) bool {
+		return !pred(e)
+	})
+}
+
+// Count returns the number of elements for which pred returns true.
+func (s *Set[T]) Count(pred func(e T) bool) int {
+	n := 0
+	for e := range s.Values() {
+		if pred(e) {
+			n++
+		}
+	}
+	return n
+}
+
+// retain removes the elements for which keep returns false,
+// preserving the order of the remaining elements.
+func (s *Set[T]) retain(keep func(e T) bool) {
+	n := 0
+	for _, e := range s.elems {
+		if !keep(e) {
+			delete(s.index, e)
+			continue
+		}
+		s.elems[n] = e
+		s.index[e] = n
+		n++
+	}
+	clear(s.elems[n:])
+	s.elems = s.elems[:n]
+}
+
 // NewSet creates and returns a new set with the given initial capacity.
 func NewSet[T comparable](initialSize int) *Set[T] {
 	initialSize = int(math.Max(float64(initialSize), 0))
 	return &Set[T]{
+		index: make(map[T]int, initialSize),
 		elems: make([]T, 0, initialSize),
 	}
 }
@@ -143,6 +384,54 @@ func FromArray[T comparable](a []T) *Set[T] {
 	return s
 }
 
+// UnionAll returns a new set with the elements of all the given sets,
+// in the order of the sets and of their elements.
+func UnionAll[T comparable](sets ...*Set[T]) *Set[T] {
+	largest := 0
+	for _, s := range sets {
+		if s.Size() > largest {
+			largest = s.Size()
+		}
+	}
+	u := NewSet[T](largest)
+	for _, s := range sets {
+		u.UnionWith(s)
+	}
+	return u
+}
+
+// IntersectAll returns a new set with the elements present in all the
+// given sets. The sets are processed from the smallest to the largest,
+// so the work is bounded by the size of the smallest set.
+func IntersectAll[T comparable](sets ...*Set[T]) *Set[T] {
+	if len(sets) == 0 {
+		return NewSet[T](0)
+	}
+	sorted := slices.Clone(sets)
+	slices.SortStableFunc(sorted, func(s1, s2 *Set[T]) int {
+		return s1.Size() - s2.Size()
+	})
+	common := sorted[0].Clone()
+	for _, s := range sorted[1:] {
+		if common.Size() == 0 {
+			break
+		}
+		common.IntersectWith(s)
+	}
+	return common
+}
+
+// Map returns a new set with the results of calling fn on every element
+// of the given set. The result is smaller than the given set if fn
+// returns the same value for different elements.
+func Map[T, U comparable](s *Set[T], fn func(e T) U) *Set[U] {
+	out := NewSet[U](s.Size())
+	for e := range s.Values() {
+		out.Add(fn(e))
+	}
+	return out
+}
+
 // bySize returns the given sets in ascending order of their sizes.
 func bySize[T comparable](s1, s2 *Set[T]) (*Set[T], *Set[T]) {
 	if s1.Size() <= s2.Size() {
@@ -150,3 +439,290 @@ func bySize[T comparable](s1, s2 *Set[T]) (*Set[T], *Set[T]) {
 	}
 	return s2, s1
 }
+
+// ConcurrentSet is the thread-safe version of Set.
+// All operations of this type are thread-safe.
+//
+// Operations between two concurrent sets lock both of them, always in
+// the order in which the sets were created, so that concurrent calls
+// like a.UnionWith(b) and b.UnionWith(a) cannot deadlock.
+type ConcurrentSet[T comparable] struct {
+	*Set[T]
+	mu sync.RWMutex
+	id uint64
+}
+
+// setIDs provides the lock order of concurrent sets.
+var setIDs atomic.Uint64
+
+// Add adds the given element to the set.
+func (s *ConcurrentSet[T]) Add(e T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Set.Add(e)
+}
+
+// AddIfAbsent adds the given element to the set if it is not present,
+// as a single atomic operation. It returns true if the element was added.
+func (s *ConcurrentSet[T]) AddIfAbsent(e T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Set.Contains(e) {
+		return false
+	}
+	s.Set.Add(e)
+	return true
+}
+
+// Remove removes the given element from the set.
+// It returns true if the element was present.
+func (s *ConcurrentSet[T]) Remove(e T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Set.Remove(e)
+}
+
+// RemoveAll removes all the given elements from the set and
+// returns the number of elements that were present.
+func (s *ConcurrentSet[T]) RemoveAll(items ...T) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Set.RemoveAll(items...)
+}
+
+// Pop removes and returns an element of the set.
+// It returns the zero value and false if the set is empty.
+func (s *ConcurrentSet[T]) Pop() (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Set.Pop()
+}
+
+// Clear removes all the elements from the set.
+func (s *ConcurrentSet[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Set.Clear()
+}
+
+// Clone returns a copy of the set.
+func (s *ConcurrentSet[T]) Clone() *ConcurrentSet[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return newConcurrentSet(s.Set.Clone())
+}
+
+// Items returns a copy of the elements of the set as a slice.
+func (s *ConcurrentSet[T]) Items() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.Items()
+}
+
+// Contains returns true if the given element is present in the set.
+func (s *ConcurrentSet[T]) Contains(e T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.Contains(e)
+}
+
+// Index returns the index of the given element in the set.
+// Returns -1 if the element is not present.
+func (s *ConcurrentSet[T]) Index(e T) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.Index(e)
+}
+
+// Size returns the number of elements in the set.
+func (s *ConcurrentSet[T]) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.Size()
+}
+
+// Union returns a new set with the elements of both the sets.
+func (s *ConcurrentSet[T]) Union(s2 *ConcurrentSet[T]) *ConcurrentSet[T] {
+	defer s.lockWith(s2, false)()
+	return newConcurrentSet(s.Set.Union(s2.inner()))
+}
+
+// Intersection returns a new set with the elements present in both the sets.
+func (s *ConcurrentSet[T]) Intersection(s2 *ConcurrentSet[T]) *ConcurrentSet[T] {
+	defer s.lockWith(s2, false)()
+	return newConcurrentSet(s.Set.Intersection(s2.inner()))
+}
+
+// Difference returns a new set with the elements of the current set
+// which are not in s2.
+func (s *ConcurrentSet[T]) Difference(s2 *ConcurrentSet[T]) *ConcurrentSet[T] {
+	defer s.lockWith(s2, false)()
+	return newConcurrentSet(s.Set.Difference(s2.inner()))
+}
+
+// SymmetricDifference returns a new set with the elements that are in
+// either the current set or s2, but not in both.
+func (s *ConcurrentSet[T]) SymmetricDifference(s2 *ConcurrentSet[T]) *ConcurrentSet[T] {
+	defer s.lockWith(s2, false)()
+	return newConcurrentSet(s.Set.SymmetricDifference(s2.inner()))
+}
+
+// UnionWith adds all elements from s2 to the current set.
+func (s *ConcurrentSet[T]) UnionWith(s2 *ConcurrentSet[T]) {
+	defer s.lockWith(s2, true)()
+	s.Set.UnionWith(s2.inner())
+}
+
+// IntersectWith removes the elements of the current set
+// which are not present in s2.
+func (s *ConcurrentSet[T]) IntersectWith(s2 *ConcurrentSet[T]) {
+	defer s.lockWith(s2, true)()
+	s.Set.IntersectWith(s2.inner())
+}
+
+// DifferenceWith removes the elements of the current set
+// which are present in s2.
+func (s *ConcurrentSet[T]) DifferenceWith(s2 *ConcurrentSet[T]) {
+	defer s.lockWith(s2, true)()
+	s.Set.DifferenceWith(s2.inner())
+}
+
+// IsDisjoint returns true if there are no common elements between
+// this set and the given set.
+func (s *ConcurrentSet[T]) IsDisjoint(s2 *ConcurrentSet[T]) bool {
+	defer s.lockWith(s2, false)()
+	return s.Set.IsDisjoint(s2.inner())
+}
+
+// IsSubsetOf returns true if this set is a subset of the given set.
+func (s *ConcurrentSet[T]) IsSubsetOf(s2 *ConcurrentSet[T]) bool {
+	defer s.lockWith(s2, false)()
+	return s.Set.IsSubsetOf(s2.inner())
+}
+
+// IsProperSubsetOf returns true if this set is a proper subset of the given set.
+func (s *ConcurrentSet[T]) IsProperSubsetOf(s2 *ConcurrentSet[T]) bool {
+	defer s.lockWith(s2, false)()
+	return s.Set.IsProperSubsetOf(s2.inner())
+}
+
+// IsSupersetOf returns true if the given set is a subset of this set.
+func (s *ConcurrentSet[T]) IsSupersetOf(s2 *ConcurrentSet[T]) bool {
+	defer s.lockWith(s2, false)()
+	return s.Set.IsSupersetOf(s2.inner())
+}
+
+// Equal returns true if both sets have the same elements.
+func (s *ConcurrentSet[T]) Equal(s2 *ConcurrentSet[T]) bool {
+	defer s.lockWith(s2, false)()
+	return s.Set.Equal(s2.inner())
+}
+
+// Values returns an iterator over a snapshot of the elements of the set,
+// so the set can be modified during iteration.
+func (s *ConcurrentSet[T]) Values() iter.Seq[T] {
+	return slices.Values(s.Items())
+}
+
+// ForEach calls fn for every element of a snapshot of the set until fn
+// returns false. The set can be modified by fn.
+func (s *ConcurrentSet[T]) ForEach(fn func(e T) bool) {
+	for _, e := range s.Items() {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+// Filter returns a new set with the elements for which pred returns true.
+// The set is read-locked while pred is called, so pred must not modify it.
+func (s *ConcurrentSet[T]) Filter(pred func(e T) bool) *ConcurrentSet[T] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return newConcurrentSet(s.Set.Filter(pred))
+}
+
+// Partition returns a new set with the elements for which pred returns
+// true and another one with the elements for which it returns false.
+// The set is read-locked while pred is called, so pred must not modify it.
+func (s *ConcurrentSet[T]) Partition(pred func(e T) bool) (in, out *ConcurrentSet[T]) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, o := s.Set.Partition(pred)
+	return newConcurrentSet(i), newConcurrentSet(o)
+}
+
+// Any returns true if pred returns true for at least one element.
+// The set is read-locked while pred is called, so pred must not modify it.
+func (s *ConcurrentSet[T]) Any(pred func(e T) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.Any(pred)
+}
+
+// All returns true if pred returns true for every element.
+// The set is read-locked while pred is called, so pred must not modify it.
+func (s *ConcurrentSet[T]) All(pred func(e T) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.All(pred)
+}
+
+// Count returns the number of elements for which pred returns true.
+// The set is read-locked while pred is called, so pred must not modify it.
+func (s *ConcurrentSet[T]) Count(pred func(e T) bool) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.Count(pred)
+}
+
+// lockWith locks the current set, for writing if write is true, and
+// read-locks s2, in the order in which the sets were created. It
+// returns the function which unlocks both.
+func (s *ConcurrentSet[T]) lockWith(s2 *ConcurrentSet[T], write bool) func() {
+	lock, unlock := s.mu.RLock, s.mu.RUnlock
+	if write {
+		lock, unlock = s.mu.Lock, s.mu.Unlock
+	}
+	if s2 == nil || s2 == s {
+		lock()
+		return unlock
+	}
+	if s.id < s2.id {
+		lock()
+		s2.mu.RLock()
+	} else {
+		s2.mu.RLock()
+		lock()
+	}
+	return func() {
+		unlock()
+		s2.mu.RUnlock()
+	}
+}
+
+// inner returns the wrapped set, nil for a nil concurrent set.
+func (s *ConcurrentSet[T]) inner() *Set[T] {
+	if s == nil {
+		return nil
+	}
+	return s.Set
+}
+
+func newConcurrentSet[T comparable](s *Set[T]) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{
+		Set: s,
+		id:  setIDs.Add(1),
+	}
+}
+
+// NewConcurrentSet creates and returns a new thread-safe set
+// with the given initial capacity.
+func NewConcurrentSet[T comparable](initialSize int) *ConcurrentSet[T] {
+	return newConcurrentSet(NewSet[T](initialSize))
+}
+
+// ConcurrentFromArray creates a new thread-safe set from the given array/slice.
+func ConcurrentFromArray[T comparable](a []T) *ConcurrentSet[T] {
+	return newConcurrentSet(FromArray(a))
+}
@@ -1,7 +1,9 @@
 package collections
 
 import (
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -15,9 +17,9 @@ func TestFromArray(t *testing.T) {
 		want *Set[T]
 	}
 	intTests := []testCase[int]{
-		{name: "ints1", args: args[int]{a: []int{1, 2, 3, 4, 1, 2, 3, 4}}, want: &Set[int]{elems: []int{1, 2, 3, 4}}},
-		{name: "ints2", args: args[int]{a: []int{3, 2, 1, 2, 1}}, want: &Set[int]{elems: []int{3, 2, 1}}},
-		{name: "ints3", args: args[int]{a: []int{}}, want: &Set[int]{make([]int, 0)}},
+		{name: "ints1", args: args[int]{a: []int{1, 2, 3, 4, 1, 2, 3, 4}}, want: &Set[int]{index: map[int]int{1: 0, 2: 1, 3: 2, 4: 3}, elems: []int{1, 2, 3, 4}}},
+		{name: "ints2", args: args[int]{a: []int{3, 2, 1, 2, 1}}, want: &Set[int]{index: map[int]int{3: 0, 2: 1, 1: 2}, elems: []int{3, 2, 1}}},
+		{name: "ints3", args: args[int]{a: []int{}}, want: &Set[int]{index: map[int]int{}, elems: make([]int, 0)}},
 	}
 	for _, tt := range intTests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -38,8 +40,8 @@ func TestNewSet(t *testing.T) {
 		want *Set[T]
 	}
 	tests := []testCase[string]{
-		{name: "size10", args: args{initialSize: 10}, want: &Set[string]{elems: make([]string, 0, 10)}},
-		{name: "size0", args: args{initialSize: 0}, want: &Set[string]{elems: make([]string, 0)}},
+		{name: "size10", args: args{initialSize: 10}, want: &Set[string]{index: map[string]int{}, elems: make([]string, 0, 10)}},
+		{name: "size0", args: args{initialSize: 0}, want: &Set[string]{index: map[string]int{}, elems: make([]string, 0)}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -51,7 +53,7 @@ func TestNewSet(t *testing.T) {
 }
 
 func TestSet_Add(t *testing.T) {
-	strSet := &Set[string]{[]string{"four"}}
+	strSet := FromArray([]string{"four"})
 	type testCase[T comparable] struct {
 		name string
 		s    *Set[T]
@@ -59,8 +61,8 @@ func TestSet_Add(t *testing.T) {
 		want *Set[T]
 	}
 	tests := []testCase[string]{
-		{"new elem", strSet, "two", &Set[string]{[]string{"four", "two"}}},
-		{"existing elem", strSet, "four", &Set[string]{[]string{"four", "two"}}},
+		{"new elem", strSet, "two", FromArray([]string{"four", "two"})},
+		{"existing elem", strSet, "four", FromArray([]string{"four", "two"})},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -73,7 +75,7 @@ func TestSet_Add(t *testing.T) {
 }
 
 func TestSet_Contains(t *testing.T) {
-	strSet := &Set[string]{[]string{"four", "two", "zero"}}
+	strSet := FromArray([]string{"four", "two", "zero"})
 	type testCase[T comparable] struct {
 		name string
 		s    *Set[T]
@@ -102,9 +104,9 @@ func TestSet_Difference(t *testing.T) {
 		want *Set[T]
 	}
 	tests := []testCase[int]{
-		{"same sets", &Set[int]{[]int{1, 2, 3}}, &Set[int]{[]int{1, 2, 3}}, &Set[int]{make([]int, 0)}},
-		{"disjoint sets", &Set[int]{[]int{1, 2, 3}}, &Set[int]{[]int{4, 5, 6}}, &Set[int]{[]int{1, 2, 3}}},
-		{"similar sets", &Set[int]{[]int{1, 2, 3, 4, 5}}, &Set[int]{[]int{3, 4, 1}}, &Set[int]{[]int{2, 5}}},
+		{"same sets", FromArray([]int{1, 2, 3}), FromArray([]int{1, 2, 3}), FromArray(make([]int, 0))},
+		{"disjoint sets", FromArray([]int{1, 2, 3}), FromArray([]int{4, 5, 6}), FromArray([]int{1, 2, 3})},
+		{"similar sets", FromArray([]int{1, 2, 3, 4, 5}), FromArray([]int{3, 4, 1}), FromArray([]int{2, 5})},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -123,9 +125,9 @@ func TestSet_Intersection(t *testing.T) {
 		want *Set[T]
 	}
 	tests := []testCase[int]{
-		{"same sets", &Set[int]{[]int{1, 2, 3}}, &Set[int]{[]int{1, 2, 3}}, &Set[int]{[]int{1, 2, 3}}},
-		{"disjoint sets", &Set[int]{[]int{1, 2, 3}}, &Set[int]{[]int{4, 5, 6}}, &Set[int]{make([]int, 0)}},
-		{"overlapping sets", &Set[int]{[]int{1, 2, 3, 4, 5}}, &Set[int]{[]int{3, 4, 1}}, &Set[int]{[]int{3, 4, 1}}},
+		{"same sets", FromArray([]int{1, 2, 3}), FromArray([]int{1, 2, 3}), FromArray([]int{1, 2, 3})},
+		{"disjoint sets", FromArray([]int{1, 2, 3}), FromArray([]int{4, 5, 6}), FromArray(make([]int, 0))},
+		{"overlapping sets", FromArray([]int{1, 2, 3, 4, 5}), FromArray([]int{3, 4, 1}), FromArray([]int{3, 4, 1})},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -144,10 +146,10 @@ func TestSet_IsSubsetOf(t *testing.T) {
 		want bool
 	}
 	tests := []testCase[string]{
-		{"same sets", &Set[string]{[]string{"two", "four"}}, &Set[string]{[]string{"four", "two"}}, true},
-		{"disjoint sets", &Set[string]{[]string{"two"}}, &Set[string]{[]string{"four"}}, false},
-		{"similar sets", &Set[string]{[]string{"four"}}, &Set[string]{[]string{"four", "two"}}, true},
-		{"empty set", &Set[string]{make([]string, 0)}, &Set[string]{[]string{"four", "two"}}, true},
+		{"same sets", FromArray([]string{"two", "four"}), FromArray([]string{"four", "two"}), true},
+		{"disjoint sets", FromArray([]string{"two"}), FromArray([]string{"four"}), false},
+		{"similar sets", FromArray([]string{"four"}), FromArray([]string{"four", "two"}), true},
+		{"empty set", FromArray(make([]string, 0)), FromArray([]string{"four", "two"}), true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -158,15 +160,99 @@ func TestSet_IsSubsetOf(t *testing.T) {
 	}
 }
 
+func TestSet_Relations(t *testing.T) {
+	type testCase[T comparable] struct {
+		name                            string
+		s                               *Set[T]
+		s2                              *Set[T]
+		subset, proper, superset, equal bool
+	}
+	tests := []testCase[string]{
+		{"same sets", FromArray([]string{"two", "four"}), FromArray([]string{"four", "two"}), true, false, true, true},
+		{"disjoint sets", FromArray([]string{"two"}), FromArray([]string{"four"}), false, false, false, false},
+		{"similar sets", FromArray([]string{"four"}), FromArray([]string{"four", "two"}), true, true, false, false},
+		{"superset", FromArray([]string{"four", "two"}), FromArray([]string{"two"}), false, false, true, false},
+		{"empty set", NewSet[string](0), FromArray([]string{"four", "two"}), true, true, false, false},
+		{"empty sets", NewSet[string](0), nil, true, false, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.IsSubsetOf(tt.s2); got != tt.subset {
+				t.Errorf("IsSubsetOf() = %v, want %v", got, tt.subset)
+			}
+			if got := tt.s.IsProperSubsetOf(tt.s2); got != tt.proper {
+				t.Errorf("IsProperSubsetOf() = %v, want %v", got, tt.proper)
+			}
+			if got := tt.s.IsSupersetOf(tt.s2); got != tt.superset {
+				t.Errorf("IsSupersetOf() = %v, want %v", got, tt.superset)
+			}
+			if got := tt.s.Equal(tt.s2); got != tt.equal {
+				t.Errorf("Equal() = %v, want %v", got, tt.equal)
+			}
+			if got := tt.s2.Equal(tt.s); got != tt.equal {
+				t.Errorf("Equal() is not symmetric")
+			}
+		})
+	}
+}
+
+func TestUnionAll(t *testing.T) {
+	tests := []struct {
+		name string
+		sets []*Set[int]
+		want []int
+	}{
+		{"no sets", nil, []int{}},
+		{"one set", []*Set[int]{FromArray([]int{3, 1})}, []int{3, 1}},
+		{"overlapping", []*Set[int]{FromArray([]int{1, 2}), nil, FromArray([]int{2, 3, 4}), FromArray([]int{5, 1})}, []int{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnionAll(tt.sets...).Items(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnionAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntersectAll(t *testing.T) {
+	tests := []struct {
+		name string
+		sets []*Set[int]
+		want []int
+	}{
+		{"no sets", nil, []int{}},
+		{"one set", []*Set[int]{FromArray([]int{3, 1})}, []int{3, 1}},
+		{"overlapping", []*Set[int]{FromArray([]int{1, 2, 3, 4, 5}), FromArray([]int{5, 4, 3}), FromArray([]int{3, 4, 9, 5, 7})}, []int{5, 4, 3}},
+		{"with empty", []*Set[int]{FromArray([]int{1, 2}), nil, FromArray([]int{1, 2})}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make([][]int, len(tt.sets))
+			for i, s := range tt.sets {
+				in[i] = s.Items()
+			}
+			if got := IntersectAll(tt.sets...).Items(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IntersectAll() = %v, want %v", got, tt.want)
+			}
+			for i, s := range tt.sets {
+				if !reflect.DeepEqual(s.Items(), in[i]) {
+					t.Errorf("IntersectAll() modified set %d", i)
+				}
+			}
+		})
+	}
+}
+
 func TestSet_Items(t *testing.T) {
 	type testCase[T comparable] struct {
 		name string
-		s    Set[T]
+		s    *Set[T]
 		want []T
 	}
 	tests := []testCase[int]{
-		{"test1", Set[int]{[]int{1, 2, 3}}, []int{1, 2, 3}},
-		{"test2", Set[int]{[]int{}}, []int{}},
+		{"test1", FromArray([]int{1, 2, 3}), []int{1, 2, 3}},
+		{"test2", FromArray([]int{}), []int{}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -180,12 +266,12 @@ func TestSet_Items(t *testing.T) {
 func TestSet_Size(t *testing.T) {
 	type testCase[T comparable] struct {
 		name string
-		s    Set[T]
+		s    *Set[T]
 		want int
 	}
 	tests := []testCase[int]{
-		{"size2", Set[int]{[]int{1, 2}}, 2},
-		{"size0", Set[int]{[]int{}}, 0},
+		{"size2", FromArray([]int{1, 2}), 2},
+		{"size0", FromArray([]int{}), 0},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -205,14 +291,470 @@ func TestSet_Union(t *testing.T) {
 		want *Set[T]
 	}
 	tests := []testCase[string]{
-		{"same sets", &Set[string]{[]string{"two", "four"}}, &Set[string]{[]string{"four", "two"}}, &Set[string]{[]string{"two", "four"}}},
-		{"disjoint sets", &Set[string]{[]string{"two"}}, &Set[string]{[]string{"four"}}, &Set[string]{[]string{"two", "four"}}},
-		{"similar sets", &Set[string]{[]string{"four"}}, &Set[string]{[]string{"four", "two"}}, &Set[string]{[]string{"two", "four"}}},
-		{"empty set", &Set[string]{make([]string, 0)}, &Set[string]{[]string{"four", "two"}}, &Set[string]{[]string{"two", "four"}}},
+		{"same sets", FromArray([]string{"two", "four"}), FromArray([]string{"four", "two"}), FromArray([]string{"two", "four"})},
+		{"disjoint sets", FromArray([]string{"two"}), FromArray([]string{"four"}), FromArray([]string{"two", "four"})},
+		{"similar sets", FromArray([]string{"four"}), FromArray([]string{"four", "two"}), FromArray([]string{"four", "two"})},
+		{"empty set", FromArray(make([]string, 0)), FromArray([]string{"four", "two"}), FromArray([]string{"four", "two"})},
+		{"nil set", FromArray([]string{"two"}), nil, FromArray([]string{"two"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, s2 := tt.s.Items(), tt.s2.Items()
+			if got := tt.s.Union(tt.s2); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Union() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.s.Items(), s) || !reflect.DeepEqual(tt.s2.Items(), s2) {
+				t.Errorf("Union() modified its operands")
+			}
+		})
+	}
+}
+
+func TestSet_Operations(t *testing.T) {
+	sets := map[string]*Set[int]{
+		"nil":      nil,
+		"empty":    NewSet[int](0),
+		"a":        FromArray([]int{1, 2, 3, 4}),
+		"overlap":  FromArray([]int{3, 4, 5, 6}),
+		"disjoint": FromArray([]int{7, 8}),
+	}
+	// reference implementations on plain maps
+	toMap := func(s *Set[int]) map[int]bool {
+		m := make(map[int]bool)
+		for _, e := range s.Items() {
+			m[e] = true
+		}
+		return m
+	}
+	ops := []struct {
+		name    string
+		pure    func(s, s2 *Set[int]) *Set[int]
+		inPlace func(s, s2 *Set[int])
+		keep    func(in1, in2 bool) bool
+	}{
+		{"Union", (*Set[int]).Union, (*Set[int]).UnionWith, func(in1, in2 bool) bool { return in1 || in2 }},
+		{"Intersection", (*Set[int]).Intersection, (*Set[int]).IntersectWith, func(in1, in2 bool) bool { return in1 && in2 }},
+		{"Difference", (*Set[int]).Difference, (*Set[int]).DifferenceWith, func(in1, in2 bool) bool { return in1 && !in2 }},
+		{"SymmetricDifference", (*Set[int]).SymmetricDifference, nil, func(in1, in2 bool) bool { return in1 != in2 }},
+	}
+	for _, op := range ops {
+		for n1, s1 := range sets {
+			for n2, s2 := range sets {
+				t.Run(fmt.Sprintf("%s/%s_%s", op.name, n1, n2), func(t *testing.T) {
+					m1, m2 := toMap(s1), toMap(s2)
+					want := make(map[int]bool)
+					for e := 0; e < 10; e++ {
+						if op.keep(m1[e], m2[e]) {
+							want[e] = true
+						}
+					}
+
+					got := op.pure(s1, s2)
+					if !reflect.DeepEqual(toMap(got), want) {
+						t.Errorf("%s() = %v, want %v", op.name, got.Items(), want)
+					}
+					if !reflect.DeepEqual(toMap(s1), m1) || !reflect.DeepEqual(toMap(s2), m2) {
+						t.Errorf("%s() modified its operands", op.name)
+					}
+
+					if op.inPlace == nil || s1 == nil {
+						return
+					}
+					c := s1.Clone()
+					op.inPlace(c, s2)
+					if !reflect.DeepEqual(toMap(c), want) {
+						t.Errorf("%sWith() = %v, want %v", op.name, c.Items(), want)
+					}
+					for i, e := range c.Items() {
+						if c.Index(e) != i {
+							t.Errorf("Index(%v) = %v, want %v", e, c.Index(e), i)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestSet_Remove(t *testing.T) {
+	type testCase[T comparable] struct {
+		name  string
+		s     *Set[T]
+		elem  T
+		want  bool
+		items []T
+	}
+	tests := []testCase[int]{
+		{"first elem", FromArray([]int{1, 2, 3}), 1, true, []int{3, 2}},
+		{"last elem", FromArray([]int{1, 2, 3}), 3, true, []int{1, 2}},
+		{"only elem", FromArray([]int{1}), 1, true, []int{}},
+		{"non existing elem", FromArray([]int{1, 2}), 5, false, []int{1, 2}},
+		{"empty set", NewSet[int](0), 5, false, []int{}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.s.Union(tt.s2)
+			if got := tt.s.Remove(tt.elem); got != tt.want {
+				t.Errorf("Remove() = %v, want %v", got, tt.want)
+			}
+			if got := tt.s.Items(); !reflect.DeepEqual(got, tt.items) {
+				t.Errorf("Items() = %v, want %v", got, tt.items)
+			}
+			if tt.s.Contains(tt.elem) {
+				t.Errorf("Contains() = true after Remove()")
+			}
+			for i, e := range tt.items {
+				if got := tt.s.Index(e); got != i {
+					t.Errorf("Index(%v) = %v, want %v", e, got, i)
+				}
+			}
+		})
+	}
+}
+
+func TestSet_RemoveAll(t *testing.T) {
+	s := FromArray([]string{"one", "two", "three", "four"})
+
+	if got := s.RemoveAll("two", "five", "four", "two"); got != 2 {
+		t.Errorf("RemoveAll() = %v, want %v", got, 2)
+	}
+	if got := s.Items(); !reflect.DeepEqual(got, []string{"one", "three"}) {
+		t.Errorf("Items() = %v, want %v", got, []string{"one", "three"})
+	}
+}
+
+func TestSet_Pop(t *testing.T) {
+	s := FromArray([]int{1, 2, 3, 4})
+	s.Remove(2)
+
+	for _, want := range []int{3, 4, 1} {
+		got, ok := s.Pop()
+		if !ok || got != want {
+			t.Errorf("Pop() = %v, %v, want %v, true", got, ok, want)
+		}
+	}
+	if got, ok := s.Pop(); ok {
+		t.Errorf("Pop() = %v, %v, want 0, false", got, ok)
+	}
+}
+
+func TestSet_Clear(t *testing.T) {
+	s := FromArray([]int{1, 2, 3})
+	s.Clear()
+
+	if s.Size() != 0 || s.Contains(1) {
+		t.Errorf("Clear() left elements %v", s.Items())
+	}
+	s.Add(3)
+	if got := s.Items(); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("Items() = %v, want %v", got, []int{3})
+	}
+}
+
+func TestSet_Clone(t *testing.T) {
+	s := FromArray([]int{1, 2, 3})
+	c := s.Clone()
+	c.Add(4)
+	c.Remove(1)
+
+	if got := s.Items(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Items() = %v, want %v", got, []int{1, 2, 3})
+	}
+	if got := c.Items(); !reflect.DeepEqual(got, []int{4, 2, 3}) {
+		t.Errorf("Items() = %v, want %v", got, []int{4, 2, 3})
+	}
+}
+
+func TestSet_ItemsCopy(t *testing.T) {
+	s := FromArray([]int{1, 2, 3})
+	s.Items()[0] = 42
+
+	if s.Contains(42) || !s.Contains(1) {
+		t.Errorf("Items() returned the internal slice")
+	}
+}
+
+func TestSet_ZeroValue(t *testing.T) {
+	var s Set[string]
+	s.Add("one")
+	s.Add("two")
+	s.Add("one")
+
+	if got := s.Items(); !reflect.DeepEqual(got, []string{"one", "two"}) {
+		t.Errorf("Items() = %v, want %v", got, []string{"one", "two"})
+	}
+	if got := s.Index("two"); got != 1 {
+		t.Errorf("Index() = %v, want %v", got, 1)
+	}
+	if got := s.Index("three"); got != -1 {
+		t.Errorf("Index() = %v, want %v", got, -1)
+	}
+}
+
+var benchSetSizes = []int{1000, 10000, 100000, 1000000}
+
+func benchSetPair(size int) (*Set[int], *Set[int]) {
+	s1, s2 := NewSet[int](size), NewSet[int](size)
+	for i := 0; i < size; i++ {
+		s1.Add(i)
+		s2.Add(i + size/2)
+	}
+	return s1, s2
+}
+
+func TestSet_Functional(t *testing.T) {
+	s := FromArray([]int{1, 2, 3, 4, 5, 6})
+	even := func(e int) bool { return e%2 == 0 }
+
+	if got := s.Filter(even).Items(); !reflect.DeepEqual(got, []int{2, 4, 6}) {
+		t.Errorf("Filter() = %v", got)
+	}
+	in, out := s.Partition(even)
+	if !reflect.DeepEqual(in.Items(), []int{2, 4, 6}) || !reflect.DeepEqual(out.Items(), []int{1, 3, 5}) {
+		t.Errorf("Partition() = %v, %v", in.Items(), out.Items())
+	}
+	if got := Map(s, func(e int) string { return fmt.Sprint(e % 3) }).Items(); !reflect.DeepEqual(got, []string{"1", "2", "0"}) {
+		t.Errorf("Map() = %v", got)
+	}
+	if got := s.Count(even); got != 3 {
+		t.Errorf("Count() = %v, want 3", got)
+	}
+	if !s.Any(even) || s.All(even) || !s.All(func(e int) bool { return e > 0 }) {
+		t.Errorf("unexpected Any() or All() result")
+	}
+	if got := s.Size(); got != 6 {
+		t.Errorf("Size() = %v after functional helpers, want 6", got)
+	}
+
+	var nilSet *Set[int]
+	if nilSet.Any(even) || !nilSet.All(even) || nilSet.Count(even) != 0 || nilSet.Filter(even).Size() != 0 {
+		t.Errorf("unexpected result for a nil set")
+	}
+}
+
+func TestSet_Values(t *testing.T) {
+	s := FromArray([]int{3, 1, 2})
+	var got []int
+	for e := range s.Values() {
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, []int{3, 1, 2}) {
+		t.Errorf("Values() = %v", got)
+	}
+
+	got = got[:0]
+	s.ForEach(func(e int) bool {
+		got = append(got, e)
+		return e != 1
+	})
+	if !reflect.DeepEqual(got, []int{3, 1}) {
+		t.Errorf("ForEach() visited %v, want it to stop after 1", got)
+	}
+
+	got = got[:0]
+	for e := range s.Values() {
+		if e == 1 {
+			break
+		}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("Values() with break = %v", got)
+	}
+}
+
+func TestConcurrentSet_Functional(t *testing.T) {
+	s := ConcurrentFromArray([]int{1, 2, 3, 4})
+	even := func(e int) bool { return e%2 == 0 }
+
+	in, out := s.Partition(even)
+	if !reflect.DeepEqual(in.Items(), []int{2, 4}) || !reflect.DeepEqual(out.Items(), []int{1, 3}) {
+		t.Errorf("Partition() = %v, %v", in.Items(), out.Items())
+	}
+	if !s.Filter(even).Equal(in) || s.Count(even) != 2 || !s.Any(even) || s.All(even) {
+		t.Errorf("unexpected result of functional helpers")
+	}
+
+	// the snapshot allows modifying the set during iteration
+	for e := range s.Values() {
+		s.Remove(e)
+	}
+	if s.Size() != 0 {
+		t.Errorf("Size() = %v after removing all elements", s.Size())
+	}
+}
+
+func TestConcurrentSet_AddIfAbsent(t *testing.T) {
+	s := NewConcurrentSet[int](0)
+	var added [100]int
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range added {
+				if s.AddIfAbsent(i) {
+					added[i]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := s.Size(); got != len(added) {
+		t.Errorf("Size() = %v, want %v", got, len(added))
+	}
+	for i, n := range added {
+		if n != 1 {
+			t.Errorf("AddIfAbsent(%v) returned true %v times, want 1", i, n)
+		}
+	}
+}
+
+func TestConcurrentSet_Concurrent(t *testing.T) {
+	s := NewConcurrentSet[int](0)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := g * 100; i < g*100+100; i++ {
+				s.Add(i)
+				s.Remove(i - 50)
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 400; i++ {
+				s.Contains(i)
+				s.Size()
+				s.Items()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, e := range s.Items() {
+		if s.Index(e) < 0 {
+			t.Errorf("Index(%v) = -1 for a present element", e)
+		}
+	}
+}
+
+func TestConcurrentSet_Operations(t *testing.T) {
+	a := ConcurrentFromArray([]int{1, 2, 3, 4})
+	b := ConcurrentFromArray([]int{3, 4, 5})
+
+	if got := a.Union(b).Items(); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := a.Intersection(b).Items(); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Errorf("Intersection() = %v", got)
+	}
+	if got := a.Difference(b).Items(); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	if got := a.SymmetricDifference(b).Items(); !reflect.DeepEqual(got, []int{1, 2, 5}) {
+		t.Errorf("SymmetricDifference() = %v", got)
+	}
+	if a.IsSubsetOf(b) || a.IsDisjoint(b) || !a.IsSupersetOf(a.Intersection(b)) || !a.Equal(a.Clone()) {
+		t.Errorf("unexpected relations between %v and %v", a.Items(), b.Items())
+	}
+	if got := a.Union(nil).Items(); !reflect.DeepEqual(got, a.Items()) {
+		t.Errorf("Union(nil) = %v", got)
+	}
+
+	// operations with itself must not deadlock
+	a.UnionWith(a)
+	if !a.Equal(a) || a.Size() != 4 {
+		t.Errorf("UnionWith(itself) = %v", a.Items())
+	}
+	a.DifferenceWith(a)
+	if a.Size() != 0 {
+		t.Errorf("DifferenceWith(itself) = %v", a.Items())
+	}
+}
+
+func TestConcurrentSet_LockOrder(t *testing.T) {
+	a := ConcurrentFromArray([]int{1, 2, 3})
+	b := ConcurrentFromArray([]int{2, 3, 4})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				a.UnionWith(b)
+				a.IntersectWith(b)
+				b.IsSubsetOf(a)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				b.UnionWith(a)
+				b.Union(a)
+				a.Equal(b)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !a.IsSubsetOf(b) {
+		t.Errorf("%v is not a subset of %v", a.Items(), b.Items())
+	}
+}
+
+func BenchmarkSet_Add(b *testing.B) {
+	for _, size := range benchSetSizes {
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			s := NewSet[int](size)
+			for i := 0; i < b.N; i++ {
+				s.Add(i % size)
+			}
+		})
+	}
+}
+
+func BenchmarkSet_Contains(b *testing.B) {
+	for _, size := range benchSetSizes {
+		s, _ := benchSetPair(size)
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Contains(i % (2 * size))
+			}
+		})
+	}
+}
+
+func BenchmarkSet_Union(b *testing.B) {
+	for _, size := range benchSetSizes {
+		s1, s2 := benchSetPair(size)
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s1.Union(s2)
+			}
+		})
+	}
+}
+
+func BenchmarkSet_Intersection(b *testing.B) {
+	for _, size := range benchSetSizes {
+		s1, s2 := benchSetPair(size)
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s1.Intersection(s2)
+			}
+		})
+	}
+}
+
+func BenchmarkSet_Difference(b *testing.B) {
+	for _, size := range benchSetSizes {
+		s1, s2 := benchSetPair(size)
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s1.Difference(s2)
+			}
 		})
 	}
 }
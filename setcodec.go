@@ -0,0 +1,365 @@
+package collections
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDuplicateElement = errors.New("duplicate element")
+	ErrUnsupportedType  = errors.New("unsupported element type")
+)
+
+// DisallowDuplicates makes UnmarshalJSON, UnmarshalText and the binary
+// decoding of the set fail with ErrDuplicateElement when the input holds
+// the same element more than once. By default duplicates are dropped.
+func (s *Set[T]) DisallowDuplicates() {
+	s.strict = true
+}
+
+// MarshalJSON encodes the set as a JSON array of its elements.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	if s.Size() == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s.elems)
+}
+
+// UnmarshalJSON replaces the elements of the set with the ones of the
+// given JSON array.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var elems []T
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	return s.replace(elems)
+}
+
+// MarshalText encodes the set as the comma-separated list of its
+// elements. It is only supported for elements of a boolean, numeric or
+// string kind. Strings must be non-empty and must not contain commas or
+// leading or trailing spaces, as UnmarshalText could not restore them.
+func (s *Set[T]) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	for i, e := range s.Items() {
+		text, err := formatText(e)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case text == "":
+			return nil, errors.New("cannot encode an empty string")
+		case strings.Contains(text, ","):
+			return nil, fmt.Errorf("cannot encode %q: contains a comma", text)
+		case strings.TrimSpace(text) != text:
+			return nil, fmt.Errorf("cannot encode %q: has leading or trailing spaces", text)
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(text)
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText replaces the elements of the set with the ones of the
+// given comma-separated list. Spaces around the elements are ignored.
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	var elems []T
+	if len(strings.TrimSpace(string(text))) > 0 {
+		for _, field := range strings.Split(string(text), ",") {
+			e, err := parseText[T](strings.TrimSpace(field))
+			if err != nil {
+				return err
+			}
+			elems = append(elems, e)
+		}
+	}
+	return s.replace(elems)
+}
+
+// EncodeBinary encodes the set in a compact binary form, with every
+// element encoded by the given codec: the number of elements followed by
+// the length and the encoding of every element, as unsigned varints.
+func (s *Set[T]) EncodeBinary(codec Codec[T]) ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(s.Size()))
+	for _, e := range s.Items() {
+		data, err := codec.Encode(e)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// DecodeBinary replaces the elements of the set with the ones encoded
+// by EncodeBinary with the given codec.
+func (s *Set[T]) DecodeBinary(data []byte, codec Codec[T]) error {
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return err
+	}
+	// every element takes at least one byte, for its length
+	if n > uint64(len(data)) {
+		return fmt.Errorf("invalid set encoding: %d elements in %d bytes", n, len(data))
+	}
+	elems := make([]T, 0, n)
+	for i := uint64(0); i < n; i++ {
+		var size uint64
+		size, data, err = readUvarint(data)
+		if err != nil {
+			return err
+		}
+		if size > uint64(len(data)) {
+			return fmt.Errorf("invalid set encoding: element %d is truncated", i)
+		}
+		e, err := codec.Decode(data[:size])
+		if err != nil {
+			return err
+		}
+		elems = append(elems, e)
+		data = data[size:]
+	}
+	if len(data) > 0 {
+		return fmt.Errorf("invalid set encoding: %d trailing bytes", len(data))
+	}
+	return s.replace(elems)
+}
+
+// MarshalBinary encodes the set with EncodeBinary, using a built-in
+// codec for elements of a boolean, numeric or string kind.
+func (s *Set[T]) MarshalBinary() ([]byte, error) {
+	return s.EncodeBinary(kindCodec[T]{})
+}
+
+// UnmarshalBinary decodes a set encoded by MarshalBinary.
+func (s *Set[T]) UnmarshalBinary(data []byte) error {
+	return s.DecodeBinary(data, kindCodec[T]{})
+}
+
+// replace replaces the elements of the set with the given ones,
+// leaving the set unchanged if they are rejected.
+func (s *Set[T]) replace(elems []T) error {
+	index := make(map[T]int, len(elems))
+	n := 0
+	for _, e := range elems {
+		if _, ok := index[e]; ok {
+			if s.strict {
+				return fmt.Errorf("%w: %v", ErrDuplicateElement, e)
+			}
+			continue
+		}
+		index[e] = n
+		elems[n] = e
+		n++
+	}
+	clear(elems[n:])
+	s.index = index
+	s.elems = elems[:n]
+	return nil
+}
+
+func readUvarint(data []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, errors.New("invalid set encoding: bad varint")
+	}
+	return v, data[n:], nil
+}
+
+// formatText formats an element of a boolean, numeric or string kind.
+func formatText[T any](e T) (string, error) {
+	v := reflect.ValueOf(e)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnsupportedType, e)
+}
+
+// parseText parses an element of a boolean, numeric or string kind.
+func parseText[T any](text string) (T, error) {
+	var e T
+	v := reflect.ValueOf(&e).Elem()
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(text, 10, v.Type().Bits())
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		u, err = strconv.ParseUint(text, 10, v.Type().Bits())
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, v.Type().Bits())
+		v.SetFloat(f)
+	default:
+		err = fmt.Errorf("%w: %T", ErrUnsupportedType, e)
+	}
+	return e, err
+}
+
+// kindCodec is the Codec used by MarshalBinary, encoding booleans as a
+// byte, integers as varints, floats as their IEEE 754 bits and strings
+// as their bytes.
+type kindCodec[T any] struct{}
+
+func (kindCodec[T]) Encode(e T) ([]byte, error) {
+	v := reflect.ValueOf(e)
+	switch v.Kind() {
+	case reflect.String:
+		return []byte(v.String()), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(nil, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(nil, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.LittleEndian.AppendUint64(nil, math.Float64bits(v.Float())), nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, e)
+}
+
+func (kindCodec[T]) Decode(data []byte) (T, error) {
+	var e T
+	v := reflect.ValueOf(&e).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(string(data))
+		return e, nil
+	case reflect.Bool:
+		if len(data) == 1 && data[0] <= 1 {
+			v.SetBool(data[0] == 1)
+			return e, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, n := binary.Varint(data); n == len(data) && !v.OverflowInt(i) {
+			v.SetInt(i)
+			return e, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u, n := binary.Uvarint(data); n == len(data) && !v.OverflowUint(u) {
+			v.SetUint(u)
+			return e, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if len(data) == 8 {
+			v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(data)))
+			return e, nil
+		}
+	default:
+		return e, fmt.Errorf("%w: %T", ErrUnsupportedType, e)
+	}
+	return e, fmt.Errorf("invalid encoding of %T: %x", e, data)
+}
+
+// MarshalJSON encodes the set as a JSON array of its elements.
+func (s *ConcurrentSet[T]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the elements of the set with the ones of the
+// given JSON array.
+func (s *ConcurrentSet[T]) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	return s.Set.UnmarshalJSON(data)
+}
+
+// MarshalText encodes the set as the comma-separated list of its elements.
+func (s *ConcurrentSet[T]) MarshalText() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.MarshalText()
+}
+
+// UnmarshalText replaces the elements of the set with the ones of the
+// given comma-separated list.
+func (s *ConcurrentSet[T]) UnmarshalText(text []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	return s.Set.UnmarshalText(text)
+}
+
+// EncodeBinary encodes the set with the given element codec.
+func (s *ConcurrentSet[T]) EncodeBinary(codec Codec[T]) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.EncodeBinary(codec)
+}
+
+// DecodeBinary replaces the elements of the set with the ones encoded
+// by EncodeBinary with the given codec.
+func (s *ConcurrentSet[T]) DecodeBinary(data []byte, codec Codec[T]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	return s.Set.DecodeBinary(data, codec)
+}
+
+// MarshalBinary encodes the set with the built-in element codec.
+func (s *ConcurrentSet[T]) MarshalBinary() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Set.MarshalBinary()
+}
+
+// UnmarshalBinary decodes a set encoded by MarshalBinary.
+func (s *ConcurrentSet[T]) UnmarshalBinary(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	return s.Set.UnmarshalBinary(data)
+}
+
+// DisallowDuplicates makes the decoding of the set fail with
+// ErrDuplicateElement when the input holds duplicates.
+func (s *ConcurrentSet[T]) DisallowDuplicates() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	s.Set.DisallowDuplicates()
+}
+
+// init initializes a zero ConcurrentSet, as allocated by the decoders
+// of encoding/json and similar packages, with the lock held.
+func (s *ConcurrentSet[T]) init() {
+	if s.Set == nil {
+		s.Set = NewSet[T](0)
+	}
+	if s.id == 0 {
+		s.id = setIDs.Add(1)
+	}
+}
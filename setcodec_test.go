@@ -0,0 +1,170 @@
+package collections
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type flag string
+
+func TestSet_JSON(t *testing.T) {
+	s := FromArray([]string{"b", "a", "c"})
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.EqualValues(t, `["b","a","c"]`, string(data))
+
+	got := NewSet[string](0)
+	require.NoError(t, json.Unmarshal(data, got))
+	require.EqualValues(t, s.Items(), got.Items())
+	require.EqualValues(t, 1, got.Index("a"))
+
+	data, err = json.Marshal(&Set[int]{})
+	require.NoError(t, err)
+	require.EqualValues(t, `[]`, string(data))
+}
+
+func TestSet_JSONField(t *testing.T) {
+	var cfg struct {
+		Allow *Set[flag] `json:"allow"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"allow": ["x", "y", "x"]}`), &cfg))
+	require.EqualValues(t, []flag{"x", "y"}, cfg.Allow.Items())
+
+	require.Error(t, json.Unmarshal([]byte(`{"allow": [1]}`), &cfg))
+	require.EqualValues(t, []flag{"x", "y"}, cfg.Allow.Items())
+}
+
+func TestSet_DisallowDuplicates(t *testing.T) {
+	s := FromArray([]int{1})
+	s.DisallowDuplicates()
+
+	err := json.Unmarshal([]byte(`[2, 3, 2]`), s)
+	require.ErrorIs(t, err, ErrDuplicateElement)
+	require.EqualValues(t, []int{1}, s.Items())
+
+	require.ErrorIs(t, s.UnmarshalText([]byte("4,4")), ErrDuplicateElement)
+	require.NoError(t, s.UnmarshalText([]byte("4,5")))
+	require.EqualValues(t, []int{4, 5}, s.Items())
+
+	data, err := FromArray([]int{6, 6}).MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, s.UnmarshalBinary(data))
+	require.True(t, s.Clone().strict)
+}
+
+func TestSet_Text(t *testing.T) {
+	tests := []struct {
+		name string
+		set  interface {
+			MarshalText() ([]byte, error)
+		}
+		want string
+	}{
+		{"strings", FromArray([]flag{"beta", "dark-mode"}), "beta,dark-mode"},
+		{"ints", FromArray([]int8{-3, 0, 127}), "-3,0,127"},
+		{"uints", FromArray([]uint{7, 1}), "7,1"},
+		{"floats", FromArray([]float64{1.5, -2}), "1.5,-2"},
+		{"bools", FromArray([]bool{true, false}), "true,false"},
+		{"empty", NewSet[string](0), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.set.MarshalText()
+			require.NoError(t, err)
+			require.EqualValues(t, tt.want, string(got))
+		})
+	}
+
+	s := NewSet[int8](0)
+	require.NoError(t, s.UnmarshalText([]byte(" -3, 0 ,127")))
+	require.EqualValues(t, []int8{-3, 0, 127}, s.Items())
+	require.NoError(t, s.UnmarshalText([]byte("")))
+	require.EqualValues(t, 0, s.Size())
+	require.Error(t, s.UnmarshalText([]byte("128")))
+
+	for _, e := range []string{"a,b", "", " a", "b\t", "\n"} {
+		_, err := FromArray([]string{e}).MarshalText()
+		require.Error(t, err, "%q", e)
+	}
+	_, err := FromArray([]string{"a", ""}).MarshalText()
+	require.Error(t, err)
+	_, err = FromArray([]temp{{1}}).MarshalText()
+	require.ErrorIs(t, err, ErrUnsupportedType)
+	require.ErrorIs(t, NewSet[temp](0).UnmarshalText([]byte("1")), ErrUnsupportedType)
+}
+
+func TestSet_TextRoundTrip(t *testing.T) {
+	for _, elems := range [][]string{
+		{"a"},
+		{"a b", "c\td"},
+		{"ü", "-", "\"q\""},
+	} {
+		data, err := FromArray(elems).MarshalText()
+		require.NoError(t, err)
+		got := FromArray([]string{"x"})
+		require.NoError(t, got.UnmarshalText(data))
+		require.EqualValues(t, elems, got.Items(), "%q", data)
+	}
+
+	floats := FromArray([]float64{math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(-1), 0.1})
+	data, err := floats.MarshalText()
+	require.NoError(t, err)
+	got := NewSet[float64](0)
+	require.NoError(t, got.UnmarshalText(data))
+	require.EqualValues(t, floats.Items(), got.Items())
+}
+
+func TestSet_Binary(t *testing.T) {
+	s := FromArray([]int{300, -1, 0, 7})
+	data, err := s.EncodeBinary(intCodec{})
+	require.NoError(t, err)
+
+	got := NewSet[int](0)
+	require.NoError(t, got.DecodeBinary(data, intCodec{}))
+	require.EqualValues(t, s.Items(), got.Items())
+
+	for i := 0; i < len(data); i++ {
+		require.Error(t, got.DecodeBinary(data[:i], intCodec{}), "truncated to %d bytes", i)
+	}
+	require.Error(t, got.DecodeBinary(append(data, 0), intCodec{}))
+	require.EqualValues(t, s.Items(), got.Items())
+}
+
+func TestSet_MarshalBinary(t *testing.T) {
+	strs := FromArray([]string{"", "a", "bc"})
+	data, err := strs.MarshalBinary()
+	require.NoError(t, err)
+	gotStrs := NewSet[string](0)
+	require.NoError(t, gotStrs.UnmarshalBinary(data))
+	require.EqualValues(t, strs.Items(), gotStrs.Items())
+
+	floats := FromArray([]float32{1.25, -3})
+	data, err = floats.MarshalBinary()
+	require.NoError(t, err)
+	gotFloats := NewSet[float32](0)
+	require.NoError(t, gotFloats.UnmarshalBinary(data))
+	require.EqualValues(t, floats.Items(), gotFloats.Items())
+
+	data, err = FromArray([]int{1000}).MarshalBinary()
+	require.NoError(t, err)
+	require.Error(t, NewSet[int8](0).UnmarshalBinary(data))
+
+	_, err = FromArray([]temp{{1}}).MarshalBinary()
+	require.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestConcurrentSet_JSON(t *testing.T) {
+	var cfg struct {
+		Allow *ConcurrentSet[string]
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"Allow": ["x", "y"]}`), &cfg))
+	require.EqualValues(t, []string{"x", "y"}, cfg.Allow.Items())
+	require.True(t, cfg.Allow.Union(ConcurrentFromArray([]string{"z"})).Contains("z"))
+
+	data, err := json.Marshal(cfg.Allow)
+	require.NoError(t, err)
+	require.EqualValues(t, `["x","y"]`, string(data))
+}
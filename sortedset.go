@@ -0,0 +1,279 @@
+package collections
+
+import "iter"
+
+// SortedSet provides a collection with no duplicates which keeps its
+// elements in sorted order, backed by an RBTree. Add, Contains and
+// Remove run in O(log n), and the elements can be iterated in order or
+// queried by range.
+//
+// Union, Intersection, Difference and SymmetricDifference return new
+// sets and never modify their operands. They merge the elements of both
+// sets in order, so they run in O(n+m), as does IsSubsetOf. Both sets
+// must use the same ordering. A nil *SortedSet is treated as an empty
+// set by all the methods that do not modify it.
+//
+// The implementation is not thread-safe.
+type SortedSet[K Comparable] struct {
+	tree *RBTree[K, struct{}]
+}
+
+// Add adds the given element to the set.
+// If the element already exists, it is a no-op.
+func (s *SortedSet[K]) Add(e K) {
+	s.tree.Put(e, struct{}{})
+}
+
+// Remove removes the given element from the set.
+// It returns true if the element was present.
+func (s *SortedSet[K]) Remove(e K) bool {
+	if !s.tree.Has(e) {
+		return false
+	}
+	s.tree.Delete(e)
+	return true
+}
+
+// Contains returns true if the given element
+// is already present in the current set, otherwise returns false.
+func (s *SortedSet[K]) Contains(e K) bool {
+	return s != nil && s.tree.Has(e)
+}
+
+// Size returns the number of elements in the current set.
+func (s *SortedSet[K]) Size() int {
+	if s == nil {
+		return 0
+	}
+	return s.tree.Size()
+}
+
+// Min returns the smallest element of the set.
+// It returns the zero value and false if the set is empty.
+func (s *SortedSet[K]) Min() (K, bool) {
+	if s.Size() == 0 {
+		var zero K
+		return zero, false
+	}
+	return s.tree.Min().Key(), true
+}
+
+// Max returns the largest element of the set.
+// It returns the zero value and false if the set is empty.
+func (s *SortedSet[K]) Max() (K, bool) {
+	if s.Size() == 0 {
+		var zero K
+		return zero, false
+	}
+	return s.tree.Max().Key(), true
+}
+
+// Floor returns the largest element less than or equal to the given one.
+// It returns the zero value and false if there is no such element.
+func (s *SortedSet[K]) Floor(e K) (K, bool) {
+	if s == nil {
+		var zero K
+		return zero, false
+	}
+	return nodeKey(s.tree.Floor(e))
+}
+
+// Ceiling returns the smallest element greater than or equal to the
+// given one. It returns the zero value and false if there is no such
+// element.
+func (s *SortedSet[K]) Ceiling(e K) (K, bool) {
+	if s == nil {
+		var zero K
+		return zero, false
+	}
+	return nodeKey(s.tree.Ceiling(e))
+}
+
+// Range returns an iterator over the elements between lo and hi, both
+// inclusive, in ascending order. The set must not be modified during
+// iteration.
+func (s *SortedSet[K]) Range(lo, hi K) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if s == nil {
+			return
+		}
+		for k := range s.tree.Range(lo, hi) {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the elements of the set in ascending
+// order. The set must not be modified during iteration.
+func (s *SortedSet[K]) Values() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if s == nil {
+			return
+		}
+		for k := range s.tree.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Items returns the elements of the set in ascending order.
+func (s *SortedSet[K]) Items() []K {
+	if s == nil {
+		return nil
+	}
+	return s.tree.Keys()
+}
+
+// Clone returns a copy of the set which can be modified
+// independently of the original.
+func (s *SortedSet[K]) Clone() *SortedSet[K] {
+	return s.build(s.Items())
+}
+
+// Union returns a new set with the elements of both the sets.
+func (s *SortedSet[K]) Union(s2 *SortedSet[K]) *SortedSet[K] {
+	return s.merge(s2, true, true, true)
+}
+
+// Intersection returns a new set with elements that are present in
+// both the sets.
+func (s *SortedSet[K]) Intersection(s2 *SortedSet[K]) *SortedSet[K] {
+	return s.merge(s2, false, true, false)
+}
+
+// Difference returns a new set with elements that are in the current
+// set but not in s2.
+func (s *SortedSet[K]) Difference(s2 *SortedSet[K]) *SortedSet[K] {
+	return s.merge(s2, true, false, false)
+}
+
+// SymmetricDifference returns a new set with the elements that are in
+// either the current set or s2, but not in both.
+func (s *SortedSet[K]) SymmetricDifference(s2 *SortedSet[K]) *SortedSet[K] {
+	return s.merge(s2, true, false, true)
+}
+
+// IsSubsetOf returns true if this set is a subset of the given set.
+func (s *SortedSet[K]) IsSubsetOf(s2 *SortedSet[K]) bool {
+	if s.Size() > s2.Size() {
+		return false
+	}
+	a, b := s.Items(), s2.Items()
+	o := s.ordering(s2)
+	j := 0
+	for _, e := range a {
+		for j < len(b) && o.compare(b[j], e) < 0 {
+			j++
+		}
+		if j == len(b) || o.compare(b[j], e) != 0 {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// Equal returns true if both sets have the same elements.
+func (s *SortedSet[K]) Equal(s2 *SortedSet[K]) bool {
+	return s.Size() == s2.Size() && s.IsSubsetOf(s2)
+}
+
+// merge walks the elements of both sets in order and returns a new set
+// with the elements only in the current set if onlyS is true, in both
+// sets if both is true and only in s2 if onlyS2 is true.
+func (s *SortedSet[K]) merge(s2 *SortedSet[K], onlyS, both, onlyS2 bool) *SortedSet[K] {
+	a, b := s.Items(), s2.Items()
+	o := s.ordering(s2)
+	out := make([]K, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch cmp := o.compare(a[i], b[j]); {
+		case cmp < 0:
+			if onlyS {
+				out = append(out, a[i])
+			}
+			i++
+		case cmp > 0:
+			if onlyS2 {
+				out = append(out, b[j])
+			}
+			j++
+		default:
+			if both {
+				out = append(out, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if onlyS {
+		out = append(out, a[i:]...)
+	}
+	if onlyS2 {
+		out = append(out, b[j:]...)
+	}
+	return o.build(out)
+}
+
+// build returns a new set with the same ordering as the current set
+// holding the given sorted elements.
+func (s *SortedSet[K]) build(keys []K) *SortedSet[K] {
+	t := NewWithComparator[K, struct{}](s.compareFn())
+	t.root = fromSorted[K, struct{}](keys)
+	return &SortedSet[K]{tree: t}
+}
+
+// ordering returns the operand whose comparator orders the result of
+// a binary operation: the current set, or s2 when the current set is nil.
+func (s *SortedSet[K]) ordering(s2 *SortedSet[K]) *SortedSet[K] {
+	if s == nil {
+		return s2
+	}
+	return s
+}
+
+func (s *SortedSet[K]) compareFn() CompareFn[K] {
+	if s == nil {
+		return nil
+	}
+	return s.tree.compareFn
+}
+
+func (s *SortedSet[K]) compare(e1, e2 K) int {
+	return compare(e1, e2, s.compareFn())
+}
+
+func nodeKey[K Comparable](n *Node[K, struct{}]) (K, bool) {
+	if n == nil {
+		var zero K
+		return zero, false
+	}
+	return n.Key(), true
+}
+
+// NewSortedSet creates and returns a new empty set
+// ordered by the natural order of its elements.
+func NewSortedSet[K Comparable]() *SortedSet[K] {
+	return NewSortedSetWithComparator[K](nil)
+}
+
+// NewSortedSetWithComparator creates and returns a new empty set
+// ordered by the given comparator.
+func NewSortedSetWithComparator[K Comparable](compareFn CompareFn[K]) *SortedSet[K] {
+	return &SortedSet[K]{
+		tree: NewWithComparator[K, struct{}](compareFn),
+	}
+}
+
+// SortedFromArray creates a new sorted set from the given array/slice.
+func SortedFromArray[K Comparable](a []K) *SortedSet[K] {
+	s := NewSortedSet[K]()
+	for _, e := range a {
+		s.Add(e)
+	}
+	return s
+}
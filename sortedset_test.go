@@ -0,0 +1,176 @@
+package collections
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedSet_Add(t *testing.T) {
+	s := NewSortedSet[int]()
+	for _, e := range []int{5, 1, 4, 1, 3, 5} {
+		s.Add(e)
+	}
+
+	require.EqualValues(t, 4, s.Size())
+	require.EqualValues(t, []int{1, 3, 4, 5}, s.Items())
+	require.True(t, s.Contains(4))
+	require.False(t, s.Contains(2))
+}
+
+func TestSortedSet_Remove(t *testing.T) {
+	s := SortedFromArray([]int{1, 2, 3})
+
+	require.True(t, s.Remove(2))
+	require.False(t, s.Remove(2))
+	require.EqualValues(t, []int{1, 3}, s.Items())
+	require.True(t, s.Remove(1))
+	require.True(t, s.Remove(3))
+	require.EqualValues(t, 0, s.Size())
+
+	_, ok := s.Min()
+	require.False(t, ok)
+}
+
+func TestSortedSet_MinMax(t *testing.T) {
+	s := SortedFromArray([]string{"pear", "apple", "fig"})
+
+	got, ok := s.Min()
+	require.True(t, ok)
+	require.EqualValues(t, "apple", got)
+	got, ok = s.Max()
+	require.True(t, ok)
+	require.EqualValues(t, "pear", got)
+}
+
+func TestSortedSet_FloorCeiling(t *testing.T) {
+	s := SortedFromArray([]int{10, 20, 30})
+
+	tests := []struct {
+		e                 int
+		floor, ceiling    int
+		hasFloor, hasCeil bool
+	}{
+		{5, 0, 10, false, true},
+		{10, 10, 10, true, true},
+		{15, 10, 20, true, true},
+		{30, 30, 30, true, true},
+		{35, 30, 0, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.e), func(t *testing.T) {
+			got, ok := s.Floor(tt.e)
+			require.EqualValues(t, tt.hasFloor, ok)
+			require.EqualValues(t, tt.floor, got)
+			got, ok = s.Ceiling(tt.e)
+			require.EqualValues(t, tt.hasCeil, ok)
+			require.EqualValues(t, tt.ceiling, got)
+		})
+	}
+}
+
+func TestSortedSet_Range(t *testing.T) {
+	s := SortedFromArray(rand.Perm(100))
+
+	require.EqualValues(t, []int{10, 11, 12, 13}, slices.Collect(s.Range(10, 13)))
+	require.EqualValues(t, []int{98, 99}, slices.Collect(s.Range(98, 200)))
+	require.Empty(t, slices.Collect(s.Range(13, 10)))
+
+	var got []int
+	for e := range s.Values() {
+		if e == 3 {
+			break
+		}
+		got = append(got, e)
+	}
+	require.EqualValues(t, []int{0, 1, 2}, got)
+}
+
+func TestSortedSet_Comparator(t *testing.T) {
+	s := NewSortedSetWithComparator(func(k1, k2 int) int { return k2 - k1 })
+	for _, e := range []int{1, 3, 2} {
+		s.Add(e)
+	}
+	s2 := NewSortedSetWithComparator(func(k1, k2 int) int { return k2 - k1 })
+	for _, e := range []int{4, 3} {
+		s2.Add(e)
+	}
+
+	require.EqualValues(t, []int{3, 2, 1}, s.Items())
+	require.EqualValues(t, []int{4, 3, 2, 1}, s.Union(s2).Items())
+	require.EqualValues(t, []int{3}, s.Intersection(s2).Items())
+	got, _ := s.Floor(0)
+	require.EqualValues(t, 1, got)
+}
+
+func TestSortedSet_NilComparator(t *testing.T) {
+	var s *SortedSet[int]
+	s2 := NewSortedSetWithComparator(func(k1, k2 int) int { return k2 - k1 })
+	for _, e := range []int{1, 3, 2} {
+		s2.Add(e)
+	}
+
+	for _, got := range []*SortedSet[int]{s.Union(s2), s.SymmetricDifference(s2), s2.Union(s)} {
+		require.EqualValues(t, []int{3, 2, 1}, got.Items())
+		requireLLRB(t, got.tree.root, got.tree.compareFn)
+		require.True(t, got.Contains(1))
+		got.Add(0)
+		require.EqualValues(t, []int{3, 2, 1, 0}, got.Items())
+	}
+	require.Empty(t, s.Intersection(s2).Items())
+	require.Empty(t, s.Difference(s2).Items())
+	require.True(t, s.IsSubsetOf(s2))
+}
+
+func TestSortedSet_Operations(t *testing.T) {
+	sets := map[string]*SortedSet[int]{
+		"nil":      nil,
+		"empty":    NewSortedSet[int](),
+		"a":        SortedFromArray([]int{1, 2, 3, 4}),
+		"overlap":  SortedFromArray([]int{3, 4, 5, 6}),
+		"subset":   SortedFromArray([]int{2, 3}),
+		"disjoint": SortedFromArray([]int{7, 8}),
+	}
+	for n1, s1 := range sets {
+		for n2, s2 := range sets {
+			t.Run(n1+"_"+n2, func(t *testing.T) {
+				// reference results computed with Set
+				r1, r2 := FromArray(s1.Items()), FromArray(s2.Items())
+				sorted := func(s *Set[int]) []int {
+					items := s.Items()
+					slices.Sort(items)
+					return items
+				}
+
+				require.EqualValues(t, sorted(r1.Union(r2)), s1.Union(s2).Items())
+				require.EqualValues(t, sorted(r1.Intersection(r2)), s1.Intersection(s2).Items())
+				require.EqualValues(t, sorted(r1.Difference(r2)), s1.Difference(s2).Items())
+				require.EqualValues(t, sorted(r1.SymmetricDifference(r2)), s1.SymmetricDifference(s2).Items())
+				require.EqualValues(t, r1.IsSubsetOf(r2), s1.IsSubsetOf(s2))
+				require.EqualValues(t, r1.Equal(r2), s1.Equal(s2))
+			})
+		}
+	}
+}
+
+func TestSortedSet_OperationsResult(t *testing.T) {
+	a := SortedFromArray(rand.Perm(500))
+	b := SortedFromArray(rand.Perm(1000)[:300])
+
+	u := a.Union(b)
+	requireLLRB(t, u.tree.root, nil)
+	u.Add(-1)
+	u.Remove(250)
+	requireLLRB(t, u.tree.root, nil)
+	require.False(t, a.Contains(-1))
+	require.True(t, a.Contains(250))
+
+	c := a.Clone()
+	c.Remove(0)
+	require.True(t, a.Contains(0))
+	require.True(t, a.IsSubsetOf(a.Union(b)))
+	require.True(t, a.Intersection(b).IsSubsetOf(b))
+}
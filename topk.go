@@ -0,0 +1,171 @@
+package collections
+
+import (
+	"sort"
+	"sync"
+)
+
+// TopK keeps the k largest elements offered to it, which makes it
+// useful for selecting the top elements of a stream in O(log k)
+// per element.
+//
+// It is backed by a bounded min-heap built on MaxPQ with the order
+// of the comparator reversed, so the smallest kept element is always
+// at the head and is the first one to be evicted.
+//
+// The implementation is not thread-safe. For a thread-safe
+// implementation, use ConcurrentTopK.
+type TopK[T comparable] struct {
+	k    int
+	cmp  Comparator[T]
+	heap *MaxPQ[T]
+}
+
+// Offer adds the given element if it is among the k largest elements
+// seen so far, evicting the current smallest element if needed.
+// It returns true if the element was kept.
+func (t *TopK[T]) Offer(item T) bool {
+	if t.k <= 0 {
+		return false
+	}
+	if t.heap.Size() < t.k {
+		t.heap.Insert(item)
+		return true
+	}
+	if t.cmp(item, t.heap.PeekMax()) <= 0 {
+		return false
+	}
+	t.heap.replaceMax(item)
+	return true
+}
+
+// Items returns the kept elements sorted in descending order.
+func (t *TopK[T]) Items() []T {
+	items := make([]T, len(t.heap.items))
+	copy(items, t.heap.items)
+	sort.Slice(items, func(i, j int) bool {
+		return t.cmp(items[i], items[j]) > 0
+	})
+	return items
+}
+
+// Threshold returns the smallest kept element and true if k elements
+// are kept. Once full, only elements greater than the threshold
+// are kept by Offer.
+// If fewer than k elements are kept, it returns the zero value and false.
+func (t *TopK[T]) Threshold() (T, bool) {
+	if t.k <= 0 || t.heap.Size() < t.k {
+		var zero T
+		return zero, false
+	}
+	return t.heap.PeekMax(), true
+}
+
+// Merge offers all the elements kept by other to the current TopK,
+// so that it keeps the k largest elements of both.
+// It is meant for combining results computed per shard.
+func (t *TopK[T]) Merge(other *TopK[T]) {
+	if other == nil || other == t {
+		return
+	}
+	for _, item := range other.heap.items {
+		t.Offer(item)
+	}
+}
+
+// Size returns the number of kept elements, which is at most K.
+func (t *TopK[T]) Size() int {
+	return t.heap.Size()
+}
+
+// K returns the maximum number of elements kept.
+func (t *TopK[T]) K() int {
+	return t.k
+}
+
+// ConcurrentTopK is the thread-safe version of TopK.
+// All operations of this type are thread-safe.
+type ConcurrentTopK[T comparable] struct {
+	*TopK[T]
+	mu sync.RWMutex
+}
+
+// Offer adds the given element if it is among the k largest elements
+// seen so far. It returns true if the element was kept.
+func (t *ConcurrentTopK[T]) Offer(item T) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.TopK.Offer(item)
+}
+
+// Items returns the kept elements sorted in descending order.
+func (t *ConcurrentTopK[T]) Items() []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.TopK.Items()
+}
+
+// Threshold returns the smallest kept element and true if k elements
+// are kept, otherwise it returns the zero value and false.
+func (t *ConcurrentTopK[T]) Threshold() (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.TopK.Threshold()
+}
+
+// Merge offers all the elements kept by other to the current TopK.
+// The elements of other are read before the current TopK is locked,
+// so merging two ConcurrentTopKs into each other cannot deadlock.
+func (t *ConcurrentTopK[T]) Merge(other *ConcurrentTopK[T]) {
+	if other == nil || other == t {
+		return
+	}
+	items := other.Items()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, item := range items {
+		t.TopK.Offer(item)
+	}
+}
+
+// Size returns the number of kept elements, which is at most K.
+func (t *ConcurrentTopK[T]) Size() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.TopK.Size()
+}
+
+// topKInitialCap bounds the capacity allocated by NewTopK, so that
+// a large k costs nothing until elements are actually kept.
+const topKInitialCap = 64
+
+// NewTopK creates a new TopK that keeps the k largest elements
+// according to compareFn. A nil compareFn compares primitive values
+// the same way MaxPQ does.
+func NewTopK[T comparable](k int, compareFn Comparator[T]) *TopK[T] {
+	if compareFn == nil {
+		compareFn = reflectCompare[T]
+	}
+	if k < 0 {
+		k = 0
+	}
+	capacity := k
+	if capacity > topKInitialCap {
+		capacity = topKInitialCap
+	}
+	return &TopK[T]{
+		k:   k,
+		cmp: compareFn,
+		heap: NewMaxPQ(uint(capacity), func(o1, o2 T) int {
+			return compareFn(o2, o1)
+		}),
+	}
+}
+
+// NewConcurrentTopK creates a new thread-safe TopK that keeps the
+// k largest elements according to compareFn.
+func NewConcurrentTopK[T comparable](k int, compareFn Comparator[T]) *ConcurrentTopK[T] {
+	return &ConcurrentTopK[T]{
+		TopK: NewTopK(k, compareFn),
+	}
+}
@@ -0,0 +1,101 @@
+package collections
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopK_Offer(t *testing.T) {
+	tk := NewTopK[int](3, nil)
+
+	require.True(t, tk.Offer(5))
+	require.True(t, tk.Offer(1))
+	require.True(t, tk.Offer(3))
+	require.False(t, tk.Offer(0))
+	require.True(t, tk.Offer(4))
+	require.False(t, tk.Offer(3))
+
+	require.EqualValues(t, 3, tk.Size())
+	require.EqualValues(t, []int{5, 4, 3}, tk.Items())
+}
+
+func TestTopK_Threshold(t *testing.T) {
+	tk := NewTopK(2, Compare)
+
+	_, ok := tk.Threshold()
+	require.False(t, ok)
+
+	tk.Offer(temp{val: 7})
+	tk.Offer(temp{val: 2})
+	got, ok := tk.Threshold()
+	require.True(t, ok)
+	require.EqualValues(t, temp{val: 2}, got)
+
+	tk.Offer(temp{val: 9})
+	got, _ = tk.Threshold()
+	require.EqualValues(t, temp{val: 7}, got)
+}
+
+func TestTopK_ZeroK(t *testing.T) {
+	tk := NewTopK[string](0, nil)
+
+	require.False(t, tk.Offer("a"))
+	require.Empty(t, tk.Items())
+}
+
+func TestTopK_LargeK(t *testing.T) {
+	tk := NewTopK[int](math.MaxInt, nil)
+	for i := 0; i < 100; i++ {
+		require.True(t, tk.Offer(i))
+	}
+
+	require.EqualValues(t, math.MaxInt, tk.K())
+	require.EqualValues(t, 100, tk.Size())
+	require.EqualValues(t, 99, tk.Items()[0])
+	_, ok := tk.Threshold()
+	require.False(t, ok)
+}
+
+func TestTopK_Merge(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	all := make([]int, 0, 1000)
+	shards := []*TopK[int]{NewTopK[int](10, nil), NewTopK[int](10, nil), NewTopK[int](10, nil)}
+	for i := 0; i < 1000; i++ {
+		v := r.Intn(10000)
+		all = append(all, v)
+		shards[i%len(shards)].Offer(v)
+	}
+	shards[0].Merge(shards[1])
+	shards[0].Merge(shards[2])
+
+	sort.Sort(sort.Reverse(sort.IntSlice(all)))
+	require.EqualValues(t, all[:10], shards[0].Items())
+}
+
+func TestConcurrentTopK_Offer(t *testing.T) {
+	tk := NewConcurrentTopK[int](5, nil)
+	other := NewConcurrentTopK[int](5, nil)
+	var wg sync.WaitGroup
+	wg.Add(4)
+	for i := 0; i < 4; i++ {
+		go func(i int) {
+			for j := i * 100; j < i*100+100; j++ {
+				tk.Offer(j)
+				other.Offer(-j)
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+	tk.Merge(other)
+
+	require.EqualValues(t, []int{399, 398, 397, 396, 395}, tk.Items())
+	got, ok := tk.Threshold()
+	require.True(t, ok)
+	require.EqualValues(t, 395, got)
+}